@@ -0,0 +1,146 @@
+package main
+
+import (
+	"Travel_Sync/internal/travel/models"
+	"Travel_Sync/internal/travel/repository"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// runBenchCandidates implements the bench-candidates subcommand: it times the candidate
+// queries used by recommendations and prints the plan Postgres picks for each. Everything
+// runs in a transaction that is rolled back, so seeded rows never persist.
+func runBenchCandidates(db *gorm.DB, args []string) {
+	fs := flag.NewFlagSet("bench-candidates", flag.ExitOnError)
+	seed := fs.Int("seed", 0, "insert this many synthetic tickets first (rolled back afterwards)")
+	runs := fs.Int("runs", 20, "timed runs per query")
+	fs.Parse(args)
+
+	// Capture the SQL of the last query so it can be explained exactly as issued
+	var lastSQL string
+	var lastVars []any
+	if err := db.Callback().Query().After("gorm:query").Register("bench:capture", func(tx *gorm.DB) {
+		lastSQL, lastVars = tx.Statement.SQL.String(), tx.Statement.Vars
+	}); err != nil {
+		log.Fatalf("bench: %v", err)
+	}
+
+	tx := db.Begin()
+	if tx.Error != nil {
+		log.Fatalf("bench: %v", tx.Error)
+	}
+	defer tx.Rollback()
+
+	if *seed > 0 {
+		if err := seedTickets(tx, *seed); err != nil {
+			log.Fatalf("bench: seeding: %v", err)
+		}
+		fmt.Printf("seeded %d tickets\n\n", *seed)
+	}
+
+	ctx := context.Background()
+	repo := repository.NewTravelTicketRepo(tx)
+	airport := "Kempegowda International Airport Terminal-1"
+	target := time.Now().UTC().Truncate(time.Hour).Add(24 * time.Hour)
+	queries := []struct {
+		name string
+		run  func() (int, error)
+	}{
+		{"GetCandidatesTimeWindowOutbound", func() (int, error) {
+			t, err := repo.GetCandidatesTimeWindowOutbound(ctx, []string{airport}, target, 2*time.Hour, time.Hour, 0)
+			return len(t), err
+		}},
+		{"GetCandidatesTimeWindowReturn", func() (int, error) {
+			t, err := repo.GetCandidatesTimeWindowReturn(ctx, []string{airport}, target, 2*time.Hour, time.Hour, 0)
+			return len(t), err
+		}},
+	}
+
+	for _, q := range queries {
+		var rows int
+		var total time.Duration
+		for i := 0; i < *runs; i++ {
+			start := time.Now()
+			n, err := q.run()
+			if err != nil {
+				log.Fatalf("bench: %s: %v", q.name, err)
+			}
+			total += time.Since(start)
+			rows = n
+		}
+		fmt.Printf("== %s: %d rows, %v avg over %d runs\n", q.name, rows, total/time.Duration(max(*runs, 1)), *runs)
+		if err := explain(tx, lastSQL, lastVars); err != nil {
+			log.Fatalf("bench: explain %s: %v", q.name, err)
+		}
+		fmt.Println()
+	}
+}
+
+// seedTickets inserts n open and closed tickets between the hostels and the airport and
+// stations, spread over the next two weeks, and refreshes planner statistics
+func seedTickets(tx *gorm.DB, n int) error {
+	hostels := models.HostelNames()
+	ends := []string{
+		"Kempegowda International Airport Terminal-1",
+		"Kempegowda International Airport Terminal-2",
+		"KSR SBC Bengaluru Junction Railway Station",
+		"SMVT Bengaluru Railway station",
+		"Yesvantpur Junction Railway station",
+	}
+	err := tx.Exec(`
+INSERT INTO travel_tickets (source, destination, empty_seats, departure_at, time_diff_mins, user_id, phone_number, status, created_at, updated_at)
+SELECT
+    CASE WHEN outbound THEN hostel ELSE stop END,
+    CASE WHEN outbound THEN stop ELSE hostel END,
+    1 + i % 3,
+    now() + (random() * interval '14 days'),
+    30,
+    1 + i % 1000,
+    '9999999999',
+    CASE WHEN i % 5 = 0 THEN 'closed' ELSE 'open' END,
+    now(), now()
+FROM (
+    SELECT i, i % 2 = 0 AS outbound,
+           (?::text[])[1 + (i / 2) % ?] AS hostel,
+           (?::text[])[1 + i % ?] AS stop
+    FROM generate_series(1, ?) AS i
+) AS s`, pqArray(hostels), len(hostels), pqArray(ends), len(ends), n).Error
+	if err != nil {
+		return err
+	}
+	return tx.Exec("ANALYZE travel_tickets").Error
+}
+
+func explain(tx *gorm.DB, sql string, vars []any) error {
+	rows, err := tx.Statement.ConnPool.QueryContext(context.Background(), "EXPLAIN (ANALYZE, BUFFERS) "+sql, vars...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return err
+		}
+		fmt.Fprintln(os.Stdout, line)
+	}
+	return rows.Err()
+}
+
+// pqArray renders a text[] literal, e.g. {"a","b"}
+func pqArray(values []string) string {
+	out := "{"
+	for i, v := range values {
+		if i > 0 {
+			out += ","
+		}
+		out += fmt.Sprintf("%q", v)
+	}
+	return out + "}"
+}
@@ -1,14 +1,42 @@
 package main
 
 import (
+	accountHandler "Travel_Sync/internal/account/handler"
+	accountRoutes "Travel_Sync/internal/account/routes"
+	accountService "Travel_Sync/internal/account/service"
+	auditHandler "Travel_Sync/internal/audit/handler"
+	auditRepo "Travel_Sync/internal/audit/repository"
+	auditRoutes "Travel_Sync/internal/audit/routes"
+	auditService "Travel_Sync/internal/audit/service"
+	"Travel_Sync/internal/cache"
+	calendarHandler "Travel_Sync/internal/calendar/handler"
+	calendarRoutes "Travel_Sync/internal/calendar/routes"
+	calendarService "Travel_Sync/internal/calendar/service"
 	"Travel_Sync/internal/config"
 	"Travel_Sync/internal/database"
+	exportHandler "Travel_Sync/internal/export/handler"
+	exportRepo "Travel_Sync/internal/export/repository"
+	exportRoutes "Travel_Sync/internal/export/routes"
+	exportService "Travel_Sync/internal/export/service"
+	"Travel_Sync/internal/jobs"
+	"Travel_Sync/internal/logging"
+	"Travel_Sync/internal/metrics"
+	notificationHandler "Travel_Sync/internal/notification/handler"
+	notificationRepo "Travel_Sync/internal/notification/repository"
+	notificationRoutes "Travel_Sync/internal/notification/routes"
+	notificationService "Travel_Sync/internal/notification/service"
+	scheduleHandler "Travel_Sync/internal/schedule/handler"
+	scheduleRepo "Travel_Sync/internal/schedule/repository"
+	scheduleRoutes "Travel_Sync/internal/schedule/routes"
+	scheduleService "Travel_Sync/internal/schedule/service"
 	"Travel_Sync/internal/security/authConfig"
 	handler2 "Travel_Sync/internal/security/handler"
 	routes2 "Travel_Sync/internal/security/routes"
 	securityService "Travel_Sync/internal/security/service"
 	"Travel_Sync/internal/server"
+	"Travel_Sync/internal/timezone"
 	travelHandler "Travel_Sync/internal/travel/handler"
+	travelModels "Travel_Sync/internal/travel/models"
 	travelRepo "Travel_Sync/internal/travel/repository"
 	travelRoutes "Travel_Sync/internal/travel/routes"
 	travelService "Travel_Sync/internal/travel/service"
@@ -16,46 +44,119 @@ import (
 	"Travel_Sync/internal/user/repository"
 	"Travel_Sync/internal/user/routes"
 	userService "Travel_Sync/internal/user/service"
+	"Travel_Sync/internal/validation"
+	waitlistHandler "Travel_Sync/internal/waitlist/handler"
+	waitlistRepo "Travel_Sync/internal/waitlist/repository"
+	waitlistRoutes "Travel_Sync/internal/waitlist/routes"
+	waitlistService "Travel_Sync/internal/waitlist/service"
 	"context"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
+	_ "time/tzdata" // embed the zone database so user timezones work on minimal images
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 )
 
 func main() {
-	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file found, reading from system env")
-	}
+	envErr := godotenv.Load()
 
 	cfg := config.LoadConfig()
+	logging.Setup(os.Stdout, cfg.LogLevel)
+	if envErr != nil {
+		slog.Info("No .env file found, reading from system env")
+	}
+	if err := timezone.SetDefault(cfg.DefaultTimezone); err != nil {
+		fatal("Invalid DEFAULT_TIMEZONE", "timezone", cfg.DefaultTimezone, "error", err)
+	}
 
 	db, err := database.Connect(cfg)
 	if err != nil {
-		log.Fatalf("Failed to connect to PostgresDB: %v", err)
+		fatal("Failed to connect to PostgresDB", "error", err)
 	}
 	defer database.Disconnect(db)
+	if sqlDB, err := db.DB(); err == nil {
+		if err := metrics.RegisterDB(sqlDB); err != nil {
+			slog.Error("Failed to register database metrics", "error", err)
+		}
+	}
+
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "migrate":
+			runMigrate(db, os.Args[2:])
+			return
+		case "bench-candidates":
+			runBenchCandidates(db, os.Args[2:])
+			return
+		}
+	}
+	ensureMigrated(db)
 
 	// --- Repos & Services ---
+	aRepo := auditRepo.NewAuditRepo(db)
+	aSvc := auditService.NewAuditService(aRepo)
+	aHandler := auditHandler.NewAuditHandler(aSvc)
+
+	recCache := travelService.NewRecommendationCache(newCache(cfg), cfg.RecommendationCacheTTL)
+
+	nRepo := notificationRepo.NewNotificationRepo(db)
+	nSvc := notificationService.NewNotificationService(nRepo)
+	nHandler := notificationHandler.NewNotificationHandler(nSvc)
+
 	userRepo := repository.NewUserRepo(db)
-	userSvc := userService.NewUserService(userRepo)
+	favRepo := repository.NewFavouriteRepo(db)
+	tRepo := travelRepo.NewTravelTicketRepo(db)
+	wRepo := waitlistRepo.NewWaitlistRepo(db)
+	wSvc := waitlistService.NewWaitlistService(wRepo, tRepo, userRepo, nSvc)
+	wHandler := waitlistHandler.NewWaitlistHandler(wSvc)
+
+	userSvc := userService.NewUserService(userRepo, favRepo, aSvc, tRepo, wSvc, recCache)
 	userHandler := handler.NewUserHandler(userSvc)
 
-	tRepo := travelRepo.NewTravelTicketRepo(db)
-	tSvc := travelService.NewTravelTicketService(tRepo, userRepo)
+	flightRepo := scheduleRepo.NewFlightScheduleRepo(db)
+	trainRepo := scheduleRepo.NewTrainTimetableRepo(db)
+	weights := travelModels.AffinityWeights{SameBatch: cfg.SameBatchWeight, Favourite: cfg.FavouriteWeight, CoTraveller: cfg.CoTravellerWeight}
+	tSvc := travelService.NewTravelTicketService(tRepo, userRepo, aSvc, flightRepo, trainRepo, favRepo, weights, wSvc, recCache)
 	tHandler := travelHandler.NewTravelTicketHandler(tSvc)
 
+	flightSvc := scheduleService.NewFlightScheduleService(flightRepo, tRepo, aSvc, wSvc, recCache)
+	trainSvc := scheduleService.NewTrainTimetableService(trainRepo)
+	sHandler := scheduleHandler.NewScheduleHandler(flightSvc, trainSvc)
+
+	calSvc := calendarService.NewCalendarService(tRepo, userRepo, wRepo, cfg.PublicBaseURL)
+	calHandler := calendarHandler.NewCalendarHandler(calSvc)
+
+	eSvc := exportService.NewExportService(exportRepo.NewExportRepo(db), userRepo, tRepo, aRepo, favRepo, wRepo, nRepo, cfg.ExportDir, cfg.ExportTTL)
+	if err := eSvc.FailInterrupted(context.Background()); err != nil {
+		slog.Error("Failed to reset interrupted exports", "error", err)
+	}
+	eHandler := exportHandler.NewExportHandler(eSvc)
+
+	// --- Background jobs ---
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	jobs.NewPurgeJob(tRepo, userRepo, wRepo, nRepo, cfg.SoftDeleteRetention, cfg.PurgeInterval).Start(jobsCtx)
+	jobs.NewExportCleanupJob(eSvc, time.Hour).Start(jobsCtx)
+	jobs.NewWaitlistExpiryJob(wSvc, 5*time.Minute).Start(jobsCtx)
+
 	oauth2Config := authConfig.GetGoogleOAuthConfig()
 	authSvc := securityService.NewAuthService(userSvc)
 	jwtSvc := securityService.NewJWTService()
 	customOAuthSvc := securityService.NewCustomOAuth2Service(oauth2Config, authSvc, jwtSvc)
 	authHandler := handler2.NewOAuthHandler(customOAuthSvc)
 
+	accSvc := accountService.NewAccountService(db, customOAuthSvc, aSvc, wSvc, recCache)
+	accHandler := accountHandler.NewAccountHandler(accSvc)
+
 	// --- Gin Router ---
+	if err := validation.Register(); err != nil {
+		fatal("Failed to register validators", "error", err)
+	}
 	ginEngine := server.NewGinRouter()
 
 	// ✅ Optional: Handle preflight requests
@@ -65,17 +166,24 @@ func main() {
 
 	// --- Register routes ---
 	routes.RegisterUserRoutes(ginEngine, userHandler, jwtSvc)
-	travelRoutes.RegisterTravelRoutes(ginEngine, tHandler, jwtSvc)
+	travelRoutes.RegisterTravelRoutes(ginEngine, tHandler, jwtSvc, cfg.AdminEmails)
 	routes2.RegisterAuthRoutes(ginEngine, authHandler, jwtSvc)
+	auditRoutes.RegisterAuditRoutes(ginEngine, aHandler, jwtSvc, cfg.AdminEmails)
+	calendarRoutes.RegisterCalendarRoutes(ginEngine, calHandler, jwtSvc)
+	exportRoutes.RegisterExportRoutes(ginEngine, eHandler, jwtSvc)
+	accountRoutes.RegisterAccountRoutes(ginEngine, accHandler, jwtSvc)
+	scheduleRoutes.RegisterScheduleRoutes(ginEngine, sHandler, jwtSvc, cfg.AdminEmails)
+	waitlistRoutes.RegisterWaitlistRoutes(ginEngine, wHandler, jwtSvc)
+	notificationRoutes.RegisterNotificationRoutes(ginEngine, nHandler, jwtSvc)
 
 	// --- Start server ---
 	addr := ":" + cfg.Port
-	log.Printf("Listening on %s", addr)
+	slog.Info("Listening", "addr", addr)
 	srv := &http.Server{Addr: addr, Handler: ginEngine}
 
 	go func() {
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("listen: %s\n", err)
+			fatal("listen", "error", err)
 		}
 	}()
 
@@ -83,11 +191,35 @@ func main() {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
-	log.Println("Shutting down server...")
+	slog.Info("Shutting down server...")
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), server.ShutdownTimeout())
 	defer cancel()
 	if err := srv.Shutdown(shutdownCtx); err != nil {
-		log.Fatalf("Server forced to shutdown: %v", err)
+		fatal("Server forced to shutdown", "error", err)
+	}
+	slog.Info("Server exiting")
+}
+
+// fatal logs at error level and exits
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+// newCache builds the configured cache backend, or nil when caching is off
+func newCache(cfg *config.AppConfig) cache.Cache {
+	switch cfg.CacheBackend {
+	case "none":
+		return nil
+	case "redis":
+		rc, err := cache.NewRedisCache(cfg.RedisURL, "travelsync:")
+		if err != nil {
+			fatal("Failed to connect to Redis", "error", err)
+		}
+		return cache.NewCounting(rc, "redis")
+	case "memory":
+		return cache.NewCounting(cache.NewMemoryCache(10000), "memory")
 	}
-	log.Println("Server exiting")
+	fatal("Invalid CACHE_BACKEND: use memory, redis or none", "cache_backend", cfg.CacheBackend)
+	return nil
 }
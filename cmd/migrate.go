@@ -0,0 +1,82 @@
+package main
+
+import (
+	"Travel_Sync/internal/database"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+
+	"gorm.io/gorm"
+)
+
+const migrateUsage = `usage: travelsync migrate <command>
+  up         apply all pending migrations
+  down [n]   revert the last n applied migrations (default 1)
+  status     list migrations and when they were applied`
+
+// runMigrate implements the migrate subcommand and exits non-zero on failure
+func runMigrate(db *gorm.DB, args []string) {
+	m, err := database.NewMigrator(db)
+	if err != nil {
+		fatal("migrate: failed to load migrations", "error", err)
+	}
+	ctx := context.Background()
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		os.Exit(2)
+	}
+	switch args[0] {
+	case "up":
+		n, err := m.Up(ctx)
+		if err != nil {
+			fatal("migrate up failed", "applied_before_failure", n, "error", err)
+		}
+		slog.Info("migrate up: applied migrations", "count", n)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				fatal("migrate down: invalid step count", "steps", args[1])
+			}
+		}
+		n, err := m.Down(ctx, steps)
+		if err != nil {
+			fatal("migrate down failed", "reverted_before_failure", n, "error", err)
+		}
+		slog.Info("migrate down: reverted migrations", "count", n)
+	case "status":
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			fatal("migrate status failed", "error", err)
+		}
+		for _, s := range statuses {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = s.AppliedAt.Format("2006-01-02 15:04:05 MST")
+			}
+			fmt.Printf("%04d  %-40s %s\n", s.Version, s.Name, applied)
+		}
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		os.Exit(2)
+	}
+}
+
+// ensureMigrated refuses to serve against a schema that is behind the binary
+func ensureMigrated(db *gorm.DB) {
+	m, err := database.NewMigrator(db)
+	if err != nil {
+		fatal("Failed to load migrations", "error", err)
+	}
+	pending, err := m.Pending(context.Background())
+	if err != nil {
+		fatal("Failed to check migrations", "error", err)
+	}
+	if len(pending) > 0 {
+		fatal("Database has pending migrations; run the migrate up subcommand first",
+			"pending", len(pending), "next", fmt.Sprintf("%04d_%s", pending[0].Version, pending[0].Name))
+	}
+}
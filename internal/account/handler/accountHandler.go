@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"Travel_Sync/internal/account/models"
+	"Travel_Sync/internal/account/service"
+	"Travel_Sync/internal/apperr"
+	"Travel_Sync/internal/middleware"
+	secservice "Travel_Sync/internal/security/service"
+	"Travel_Sync/internal/validation"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AccountHandler struct {
+	Svc *service.AccountService
+}
+
+func NewAccountHandler(svc *service.AccountService) *AccountHandler {
+	return &AccountHandler{Svc: svc}
+}
+
+// DeleteAccount permanently deletes the authenticated user's account and logs them out
+func (h *AccountHandler) DeleteAccount(c *gin.Context) {
+	jwtClaims, exists := c.Get("jwt_claims")
+	if !exists {
+		c.Error(apperr.ErrUnauthorized)
+		return
+	}
+	claims, ok := jwtClaims.(*secservice.CustomClaims)
+	if !ok {
+		c.Error(apperr.ErrInvalidUserContext)
+		return
+	}
+
+	var dto models.AccountDeleteDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.Error(validation.BindError(err))
+		return
+	}
+
+	err := h.Svc.DeleteAccount(c.Request.Context(), claims.UserID, dto.ConfirmEmail, claims.AccessToken, claims.RefreshToken, middleware.GetRequestID(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Clear JWT cookie with same settings as when it was set
+	c.Header("Set-Cookie", "jwt_token=; Path=/; Max-Age=0; HttpOnly; Secure; SameSite=None")
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": "Account deleted successfully"})
+}
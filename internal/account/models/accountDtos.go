@@ -0,0 +1,6 @@
+package models
+
+// AccountDeleteDto must repeat the account email to confirm the deletion
+type AccountDeleteDto struct {
+	ConfirmEmail string `json:"confirm_email" binding:"required,email"`
+}
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"Travel_Sync/internal/account/handler"
+	"Travel_Sync/internal/middleware"
+	"Travel_Sync/internal/security/config"
+	"Travel_Sync/internal/security/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterAccountRoutes(router *gin.Engine, accountHandler *handler.AccountHandler, jwtService *service.JWTService) {
+	api := router.Group("/api")
+	{
+		account := api.Group("/account")
+		account.Use(config.JWTMiddleware(jwtService))
+		// Deleting an account is rare; use the stricter auth limits
+		account.Use(middleware.AuthRateLimiter())
+		{
+			account.DELETE("", accountHandler.DeleteAccount)
+		}
+	}
+}
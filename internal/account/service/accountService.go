@@ -0,0 +1,176 @@
+package service
+
+import (
+	"Travel_Sync/internal/apperr"
+	aentity "Travel_Sync/internal/audit/entity"
+	amodels "Travel_Sync/internal/audit/models"
+	aservice "Travel_Sync/internal/audit/service"
+	eentity "Travel_Sync/internal/export/entity"
+	nentity "Travel_Sync/internal/notification/entity"
+	secservice "Travel_Sync/internal/security/service"
+	tentity "Travel_Sync/internal/travel/entity"
+	tservice "Travel_Sync/internal/travel/service"
+	uentity "Travel_Sync/internal/user/entity"
+	wentity "Travel_Sync/internal/waitlist/entity"
+	wmodels "Travel_Sync/internal/waitlist/models"
+	wservice "Travel_Sync/internal/waitlist/service"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"os"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrConfirmationMismatch = apperr.Validation("confirmation_mismatch", "confirmation does not match your account email")
+	ErrUserNotFound         = apperr.NotFound("user_not_found", "User not found")
+)
+
+type AccountService struct {
+	DB              *gorm.DB
+	OAuth           *secservice.CustomOAuth2Service
+	Audit           *aservice.AuditService
+	Waitlist        *wservice.WaitlistService
+	Recommendations *tservice.RecommendationCache
+}
+
+func NewAccountService(db *gorm.DB, oauth *secservice.CustomOAuth2Service, audit *aservice.AuditService, waitlist *wservice.WaitlistService, recommendations *tservice.RecommendationCache) *AccountService {
+	return &AccountService{DB: db, OAuth: oauth, Audit: audit, Waitlist: waitlist, Recommendations: recommendations}
+}
+
+// deletedUser is all the audit log keeps of a deleted account: the ID, and a hash of the
+// email so that the entry can be matched to an address someone already knows, without
+// storing the address or any other personal data
+type deletedUser struct {
+	ID          int64  `json:"id"`
+	EmailSHA256 string `json:"email_sha256"`
+}
+
+func emailHash(email string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(email))))
+	return hex.EncodeToString(sum[:])
+}
+
+// DeleteAccount permanently removes the user and everything tied to them.
+// Tickets are closed and removed, data export archives are deleted, personal data is
+// scrubbed from the user's audit history, the user row is hard-deleted and finally the
+// Google tokens are revoked. Riders waiting on the
+// user's tickets are told the trips are cancelled, and cached recommendations that
+// could list the tickets are dropped.
+func (s *AccountService) DeleteAccount(ctx context.Context, userID int64, confirmEmail, accessToken, refreshToken, requestID string) error {
+	var user uentity.User
+	if err := s.DB.WithContext(ctx).First(&user, userID).Error; err != nil {
+		return apperr.NotFoundAs(err, ErrUserNotFound)
+	}
+	if !strings.EqualFold(strings.TrimSpace(confirmEmail), user.Email) {
+		return ErrConfirmationMismatch
+	}
+
+	var exportFiles []string
+	var tickets []tentity.TravelTicket
+	var waiting []wentity.WaitlistEntry
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Kept to notify waiting riders and invalidate cached results once the rows are gone
+		if err := tx.Where("user_id = ?", userID).Find(&tickets).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("status = ? AND ticket_id IN (SELECT id FROM travel_tickets WHERE user_id = ?)", wmodels.StatusWaiting, userID).
+			Order("created_at, id").Find(&waiting).Error; err != nil {
+			return err
+		}
+
+		// Waitlist entries by the user and on the user's tickets, and the user's notifications
+		if err := tx.Where("user_id = ? OR ticket_id IN (SELECT id FROM travel_tickets WHERE user_id = ?)", userID, userID).
+			Delete(&wentity.WaitlistEntry{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&nentity.Notification{}).Error; err != nil {
+			return err
+		}
+
+		// Close first so nothing reading concurrently treats the tickets as available
+		if err := tx.Model(&tentity.TravelTicket{}).
+			Where("user_id = ?", userID).
+			Update("status", "closed").Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&tentity.TravelTicket{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&eentity.ExportJob{}).
+			Where("user_id = ? AND file_path <> ''", userID).
+			Pluck("file_path", &exportFiles).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&eentity.ExportJob{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("user_id = ? OR favourite_id = ?", userID, userID).Delete(&uentity.FavouriteTraveller{}).Error; err != nil {
+			return err
+		}
+
+		// The history of the user's data stays, but without the personal data in it
+		var logs []aentity.AuditLog
+		if err := tx.Where("owner_id = ?", userID).Find(&logs).Error; err != nil {
+			return err
+		}
+		for _, l := range logs {
+			changes, scrubbed, err := aservice.ScrubPersonal(l.EntityType, l.Changes)
+			if err != nil {
+				return err
+			}
+			if !scrubbed {
+				continue
+			}
+			if err := tx.Model(&aentity.AuditLog{}).Where("id = ?", l.ID).Update("changes", changes).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Unscoped().Delete(&uentity.User{ID: userID}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, f := range exportFiles {
+		if err := os.Remove(f); err != nil && !os.IsNotExist(err) {
+			slog.ErrorContext(ctx, "account: failed to remove export archive", "path", f, "error", err)
+		}
+	}
+
+	s.Audit.Record(ctx, amodels.AuditEntry{
+		ActorID:    userID,
+		OwnerID:    userID,
+		Action:     amodels.ActionDelete,
+		EntityType: amodels.EntityUser,
+		EntityID:   userID,
+		Before:     &deletedUser{ID: userID, EmailSHA256: emailHash(user.Email)},
+		RequestID:  requestID,
+	})
+
+	byTicket := make(map[int64][]wentity.WaitlistEntry)
+	for _, e := range waiting {
+		byTicket[e.TicketID] = append(byTicket[e.TicketID], e)
+	}
+	for i := range tickets {
+		s.Waitlist.NotifyCancelled(ctx, &tickets[i], byTicket[tickets[i].ID])
+	}
+	ticketPtrs := make([]*tentity.TravelTicket, 0, len(tickets))
+	for i := range tickets {
+		ticketPtrs = append(ticketPtrs, &tickets[i])
+	}
+	s.Recommendations.Invalidate(ticketPtrs...)
+	s.Recommendations.InvalidateUsers(userID)
+
+	// The account is gone either way; a token that is already expired or revoked is not an error for the user
+	if err := s.OAuth.RevokeGoogleToken(ctx, accessToken, refreshToken); err != nil {
+		slog.WarnContext(ctx, "account: failed to revoke Google tokens", "user_id", userID, "error", err)
+	}
+	return nil
+}
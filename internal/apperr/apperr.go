@@ -0,0 +1,170 @@
+// Package apperr defines the errors services return to handlers. Each error has a Kind, which
+// decides the HTTP status, and a stable machine-readable Code that clients can switch on; the
+// Message is for people and may change. middleware.ErrorRenderer turns them into responses.
+package apperr
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"gorm.io/gorm"
+)
+
+type Kind string
+
+const (
+	KindValidation    Kind = "validation"
+	KindUnprocessable Kind = "unprocessable" // well-formed, but fields break the rules
+	KindUnauthorized  Kind = "unauthorized"
+	KindForbidden     Kind = "forbidden"
+	KindNotFound      Kind = "not_found"
+	KindConflict      Kind = "conflict"
+	KindRateLimited   Kind = "rate_limited"
+	KindTimeout       Kind = "timeout"
+	KindCanceled      Kind = "canceled"
+	KindInternal      Kind = "internal"
+)
+
+// StatusClientClosedRequest is the (nginx) status logged when the client went away
+const StatusClientClosedRequest = 499
+
+// Status is the HTTP status a kind is rendered with
+func (k Kind) Status() int {
+	switch k {
+	case KindValidation:
+		return http.StatusBadRequest
+	case KindUnprocessable:
+		return http.StatusUnprocessableEntity
+	case KindUnauthorized:
+		return http.StatusUnauthorized
+	case KindForbidden:
+		return http.StatusForbidden
+	case KindNotFound:
+		return http.StatusNotFound
+	case KindConflict:
+		return http.StatusConflict
+	case KindRateLimited:
+		return http.StatusTooManyRequests
+	case KindTimeout:
+		return http.StatusGatewayTimeout
+	case KindCanceled:
+		return StatusClientClosedRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// FieldError says what is wrong with one field of a request; Field is the JSON name
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	// Fields lists the offending fields of a validation error
+	Fields []FieldError
+	// Details are extra fields rendered next to the error, e.g. retry_after
+	Details map[string]any
+	// Err is the underlying cause; it is logged but never shown to clients
+	Err error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is matches errors by code, so errors.Is(err, ErrTicketNotFound) holds for any ticket_not_found
+// error, including ones built with a more specific message
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// Wrap returns a copy of e with err as its cause
+func (e *Error) Wrap(err error) *Error {
+	c := *e
+	c.Err = err
+	return &c
+}
+
+// WithMessage returns a copy of e with another message, for messages that carry values
+func (e *Error) WithMessage(msg string) *Error {
+	c := *e
+	c.Message = msg
+	return &c
+}
+
+func New(kind Kind, code, msg string) *Error {
+	return &Error{Kind: kind, Code: code, Message: msg}
+}
+
+func Validation(code, msg string) *Error   { return New(KindValidation, code, msg) }
+func Unauthorized(code, msg string) *Error { return New(KindUnauthorized, code, msg) }
+func Forbidden(code, msg string) *Error    { return New(KindForbidden, code, msg) }
+func NotFound(code, msg string) *Error     { return New(KindNotFound, code, msg) }
+func Conflict(code, msg string) *Error     { return New(KindConflict, code, msg) }
+
+// Invalid reports one or more invalid request fields
+func Invalid(fields ...FieldError) *Error {
+	e := New(KindUnprocessable, "validation_failed", "some fields are invalid")
+	e.Fields = fields
+	return e
+}
+
+// RateLimited tells the client to come back after retryAfter seconds
+func RateLimited(msg string, retryAfter float64) *Error {
+	e := New(KindRateLimited, "rate_limited", msg)
+	e.Details = map[string]any{"retry_after": retryAfter}
+	return e
+}
+
+// Generic errors for causes that carry no domain meaning of their own
+var (
+	ErrUnauthorized       = Unauthorized("unauthorized", "unauthorized")
+	ErrInvalidUserContext = Unauthorized("invalid_user_context", "invalid user context")
+	ErrForbidden          = Forbidden("forbidden", "forbidden")
+	ErrNotFound           = NotFound("not_found", "not found")
+	ErrInvalidBody        = Validation("invalid_body", "invalid request body")
+	ErrInvalidID          = Validation("invalid_id", "invalid id")
+	ErrTimeout            = New(KindTimeout, "timeout", "the request took too long, please try again")
+	ErrCanceled           = New(KindCanceled, "canceled", "the request was canceled")
+	ErrInternal           = New(KindInternal, "internal", "something went wrong, please try again")
+)
+
+// NotFoundAs replaces a missing-row error from a repository with notFound and passes any
+// other error through
+func NotFoundAs(err error, notFound *Error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return notFound.Wrap(err)
+	}
+	return err
+}
+
+// From classifies any error. Domain errors are returned as they are; missing rows, deadlines
+// and cancellations get their generic kinds, and everything else is internal with err as the
+// cause, so database and other messages never reach the client.
+func From(err error) *Error {
+	var e *Error
+	switch {
+	case errors.As(err, &e):
+		return e
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrNotFound.Wrap(err)
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrTimeout.Wrap(err)
+	case errors.Is(err, context.Canceled):
+		return ErrCanceled.Wrap(err)
+	}
+	return ErrInternal.Wrap(err)
+}
@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+// AuditLog is an append-only record of a change made to a user-owned entity.
+// Rows are only ever inserted; nothing updates or deletes them, except that erasing an
+// account scrubs the personal data out of its rows.
+type AuditLog struct {
+	ID         int64     `gorm:"primaryKey;autoIncrement;not null" json:"id"`
+	ActorID    int64     `gorm:"not null;index" json:"actor_id"` // 0 when the change was made by the system
+	OwnerID    int64     `gorm:"not null;index" json:"owner_id"` // user the changed entity belongs to
+	Action     string    `gorm:"type:varchar(32);not null" json:"action"`
+	EntityType string    `gorm:"type:varchar(32);not null;index:idx_audit_logs_entity" json:"entity_type"`
+	EntityID   int64     `gorm:"not null;index:idx_audit_logs_entity" json:"entity_id"`
+	Changes    string    `gorm:"type:jsonb;not null;default:'{}'" json:"changes"`
+	RequestID  string    `gorm:"size:64" json:"request_id"`
+	CreatedAt  time.Time `gorm:"autoCreateTime;index" json:"created_at"`
+}
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"Travel_Sync/internal/apperr"
+	"Travel_Sync/internal/audit/models"
+	"Travel_Sync/internal/audit/service"
+	"Travel_Sync/internal/validation"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+type AuditHandler struct {
+	Svc *service.AuditService
+}
+
+func NewAuditHandler(svc *service.AuditService) *AuditHandler {
+	return &AuditHandler{Svc: svc}
+}
+
+// GetMyHistory returns the changes made by or to the authenticated user
+func (h *AuditHandler) GetMyHistory(c *gin.Context) {
+	uid, ok := c.Get("user_id")
+	if !ok {
+		c.Error(apperr.ErrUnauthorized)
+		return
+	}
+	limit, offset, ok := parsePaging(c)
+	if !ok {
+		return
+	}
+	logs, err := h.Svc.GetUserHistory(c.Request.Context(), toInt64(uid), limit, offset)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": logs})
+}
+
+// Search lets admins query the whole audit log
+func (h *AuditHandler) Search(c *gin.Context) {
+	limit, offset, ok := parsePaging(c)
+	if !ok {
+		return
+	}
+	var filter models.AuditFilter
+	var err error
+	if filter.ActorID, err = queryInt64(c, "actor_id"); err != nil {
+		c.Error(invalidParam("actor_id"))
+		return
+	}
+	if filter.OwnerID, err = queryInt64(c, "owner_id"); err != nil {
+		c.Error(invalidParam("owner_id"))
+		return
+	}
+	if filter.EntityID, err = queryInt64(c, "entity_id"); err != nil {
+		c.Error(invalidParam("entity_id"))
+		return
+	}
+	filter.EntityType = c.Query("entity_type")
+	filter.Action = c.Query("action")
+	for _, key := range []string{"since", "until"} {
+		if fe := validation.Var(key, c.Query(key), "omitempty,rfc3339instant"); fe != nil {
+			c.Error(apperr.Invalid(*fe))
+			return
+		}
+	}
+	filter.Since = queryTime(c, "since")
+	filter.Until = queryTime(c, "until")
+
+	logs, err := h.Svc.Search(c.Request.Context(), filter, limit, offset)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": logs})
+}
+
+func parsePaging(c *gin.Context) (int, int, bool) {
+	limit := defaultPageSize
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			c.Error(invalidParam("limit"))
+			return 0, 0, false
+		}
+		limit = n
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+	offset := 0
+	if v := c.Query("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			c.Error(invalidParam("offset"))
+			return 0, 0, false
+		}
+		offset = n
+	}
+	return limit, offset, true
+}
+
+func queryInt64(c *gin.Context, key string) (int64, error) {
+	v := c.Query(key)
+	if v == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(v, 10, 64)
+}
+
+// queryTime reads an RFC3339 query parameter that has already been validated
+func queryTime(c *gin.Context, key string) *time.Time {
+	t, err := time.Parse(time.RFC3339, c.Query(key))
+	if err != nil {
+		return nil
+	}
+	t = t.UTC()
+	return &t
+}
+
+// invalidParam reports a query parameter that is not a valid number for its use
+func invalidParam(key string) error {
+	msg := "must be a positive number"
+	if key == "offset" {
+		msg = "must be zero or a positive number"
+	}
+	return apperr.Invalid(apperr.FieldError{Field: key, Code: "number", Message: msg})
+}
+
+func toInt64(v interface{}) int64 {
+	if id, ok := v.(int64); ok {
+		return id
+	}
+	if f, ok := v.(float64); ok {
+		return int64(f)
+	}
+	return 0
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Audit actions
+const (
+	ActionCreate  = "create"
+	ActionUpdate  = "update"
+	ActionDelete  = "delete"
+	ActionRestore = "restore"
+)
+
+// Audited entity types
+const (
+	EntityTicket = "ticket"
+	EntityUser   = "user"
+)
+
+// AuditEntry describes a single change to be recorded. Before is nil for
+// creations and After is nil for deletions.
+type AuditEntry struct {
+	ActorID    int64
+	OwnerID    int64
+	Action     string
+	EntityType string
+	EntityID   int64
+	Before     interface{}
+	After      interface{}
+	RequestID  string
+}
+
+// FieldChange holds the old and new value of a single field
+type FieldChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// AuditFilter narrows down audit log queries. Zero values are ignored.
+type AuditFilter struct {
+	ActorID    int64
+	OwnerID    int64
+	EntityType string
+	EntityID   int64
+	Action     string
+	Since      *time.Time
+	Until      *time.Time
+}
+
+type AuditLogResponseDto struct {
+	ID         int64           `json:"id"`
+	ActorID    int64           `json:"actor_id"`
+	OwnerID    int64           `json:"owner_id"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entity_type"`
+	EntityID   int64           `json:"entity_id"`
+	Changes    json.RawMessage `json:"changes"`
+	RequestID  string          `json:"request_id,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
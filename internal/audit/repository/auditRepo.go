@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"Travel_Sync/internal/audit/entity"
+	"Travel_Sync/internal/audit/models"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type AuditRepo struct {
+	DB *gorm.DB
+}
+
+func NewAuditRepo(db *gorm.DB) *AuditRepo {
+	return &AuditRepo{DB: db}
+}
+
+// Create appends a new audit log row. There is intentionally no update or delete.
+func (r *AuditRepo) Create(ctx context.Context, log *entity.AuditLog) error {
+	return r.DB.WithContext(ctx).Create(log).Error
+}
+
+// ListForUser returns entries the user either made or that concern the user's own data, newest first
+func (r *AuditRepo) ListForUser(ctx context.Context, userID int64, limit, offset int) ([]entity.AuditLog, error) {
+	var logs []entity.AuditLog
+	err := r.DB.WithContext(ctx).Where("owner_id = ? OR actor_id = ?", userID, userID).
+		Order("created_at DESC, id DESC").
+		Limit(limit).Offset(offset).
+		Find(&logs).Error
+	return logs, err
+}
+
+// ListAllForUser returns every entry ListForUser would, oldest first and without paging
+func (r *AuditRepo) ListAllForUser(ctx context.Context, userID int64) ([]entity.AuditLog, error) {
+	var logs []entity.AuditLog
+	err := r.DB.WithContext(ctx).Where("owner_id = ? OR actor_id = ?", userID, userID).
+		Order("created_at, id").
+		Find(&logs).Error
+	return logs, err
+}
+
+// List returns entries matching the filter, newest first
+func (r *AuditRepo) List(ctx context.Context, filter models.AuditFilter, limit, offset int) ([]entity.AuditLog, error) {
+	var logs []entity.AuditLog
+	q := r.DB.WithContext(ctx).Model(&entity.AuditLog{})
+	if filter.ActorID != 0 {
+		q = q.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.OwnerID != 0 {
+		q = q.Where("owner_id = ?", filter.OwnerID)
+	}
+	if filter.EntityType != "" {
+		q = q.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != 0 {
+		q = q.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.Action != "" {
+		q = q.Where("action = ?", filter.Action)
+	}
+	if filter.Since != nil {
+		q = q.Where("created_at >= ?", *filter.Since)
+	}
+	if filter.Until != nil {
+		q = q.Where("created_at < ?", *filter.Until)
+	}
+	err := q.Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&logs).Error
+	return logs, err
+}
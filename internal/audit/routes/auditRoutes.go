@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"Travel_Sync/internal/audit/handler"
+	"Travel_Sync/internal/middleware"
+	"Travel_Sync/internal/security/config"
+	"Travel_Sync/internal/security/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterAuditRoutes(router *gin.Engine, auditHandler *handler.AuditHandler, jwtService *service.JWTService, adminEmails []string) {
+	api := router.Group("/api")
+	{
+		audit := api.Group("/audit")
+		audit.Use(config.JWTMiddleware(jwtService))
+		audit.Use(middleware.GeneralRateLimiter())
+		{
+			audit.GET("/me", auditHandler.GetMyHistory)
+
+			// Full audit log is restricted to admins
+			admin := audit.Group("")
+			admin.Use(config.AdminMiddleware(adminEmails))
+			{
+				admin.GET("", auditHandler.Search)
+			}
+		}
+	}
+}
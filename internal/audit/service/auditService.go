@@ -0,0 +1,183 @@
+package service
+
+import (
+	"Travel_Sync/internal/audit/entity"
+	"Travel_Sync/internal/audit/models"
+	"Travel_Sync/internal/audit/repository"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"reflect"
+)
+
+// fields that change on every write and carry no useful history
+var ignoredFields = map[string]struct{}{
+	"created_at": {},
+	"updated_at": {},
+	"CreatedAt":  {},
+	"UpdatedAt":  {},
+}
+
+type AuditService struct {
+	Repo *repository.AuditRepo
+}
+
+func NewAuditService(repo *repository.AuditRepo) *AuditService {
+	return &AuditService{Repo: repo}
+}
+
+// Record stores an audit entry. Failures are logged and never bubble up so that
+// auditing cannot break the change being audited. The change is already made, so the
+// entry is written even if ctx is cancelled meanwhile. A nil service is a no-op.
+func (s *AuditService) Record(ctx context.Context, e models.AuditEntry) {
+	if s == nil || s.Repo == nil {
+		return
+	}
+	ctx = context.WithoutCancel(ctx)
+	changes, err := Diff(e.Before, e.After)
+	if err != nil {
+		slog.ErrorContext(ctx, "audit: failed to diff", "entity_type", e.EntityType, "entity_id", e.EntityID, "request_id", e.RequestID, "error", err)
+		return
+	}
+	if e.Action == models.ActionUpdate && len(changes) == 0 {
+		return
+	}
+	raw, err := json.Marshal(changes)
+	if err != nil {
+		slog.ErrorContext(ctx, "audit: failed to encode changes", "entity_type", e.EntityType, "entity_id", e.EntityID, "request_id", e.RequestID, "error", err)
+		return
+	}
+	row := &entity.AuditLog{
+		ActorID:    e.ActorID,
+		OwnerID:    e.OwnerID,
+		Action:     e.Action,
+		EntityType: e.EntityType,
+		EntityID:   e.EntityID,
+		Changes:    string(raw),
+		RequestID:  e.RequestID,
+	}
+	if err := s.Repo.Create(ctx, row); err != nil {
+		slog.ErrorContext(ctx, "audit: failed to record", "action", e.Action, "entity_type", e.EntityType, "entity_id", e.EntityID, "request_id", e.RequestID, "error", err)
+	}
+}
+
+// GetUserHistory returns the audit trail visible to the given user
+func (s *AuditService) GetUserHistory(ctx context.Context, userID int64, limit, offset int) ([]models.AuditLogResponseDto, error) {
+	logs, err := s.Repo.ListForUser(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return toResponseDtos(logs), nil
+}
+
+// Search returns audit entries matching the filter (admin only)
+func (s *AuditService) Search(ctx context.Context, filter models.AuditFilter, limit, offset int) ([]models.AuditLogResponseDto, error) {
+	logs, err := s.Repo.List(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return toResponseDtos(logs), nil
+}
+
+// redactedValue replaces personal data scrubbed from the history of an erased account
+const redactedValue = "[REDACTED]"
+
+// personalFields are the audited fields, per entity type, that identify a person
+var personalFields = map[string]map[string]struct{}{
+	models.EntityUser:   {"Name": {}, "Email": {}, "PhoneNumber": {}, "Gender": {}},
+	models.EntityTicket: {"phone_number": {}, "vehicle_details": {}},
+}
+
+// ScrubPersonal replaces the values of personal fields in an entry's changes with
+// [REDACTED], keeping the record that they changed. It reports whether anything was replaced.
+func ScrubPersonal(entityType, changes string) (string, bool, error) {
+	fields := personalFields[entityType]
+	var parsed map[string]models.FieldChange
+	if err := json.Unmarshal([]byte(changes), &parsed); err != nil {
+		return "", false, err
+	}
+	scrubbed := false
+	for k, c := range parsed {
+		if _, ok := fields[k]; !ok {
+			continue
+		}
+		if c.From != nil {
+			c.From = redactedValue
+		}
+		if c.To != nil {
+			c.To = redactedValue
+		}
+		parsed[k] = c
+		scrubbed = true
+	}
+	if !scrubbed {
+		return changes, false, nil
+	}
+	raw, err := json.Marshal(parsed)
+	return string(raw), true, err
+}
+
+// Diff compares the JSON representation of before and after and returns the
+// fields whose values differ. Either side may be nil.
+func Diff(before, after interface{}) (map[string]models.FieldChange, error) {
+	b, err := toFieldMap(before)
+	if err != nil {
+		return nil, err
+	}
+	a, err := toFieldMap(after)
+	if err != nil {
+		return nil, err
+	}
+	changes := make(map[string]models.FieldChange)
+	for k, av := range a {
+		if _, skip := ignoredFields[k]; skip {
+			continue
+		}
+		bv, ok := b[k]
+		if !ok || !reflect.DeepEqual(av, bv) {
+			changes[k] = models.FieldChange{From: bv, To: av}
+		}
+	}
+	for k, bv := range b {
+		if _, skip := ignoredFields[k]; skip {
+			continue
+		}
+		if _, ok := a[k]; !ok {
+			changes[k] = models.FieldChange{From: bv, To: nil}
+		}
+	}
+	return changes, nil
+}
+
+func toFieldMap(v interface{}) (map[string]interface{}, error) {
+	out := map[string]interface{}{}
+	if v == nil || (reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil()) {
+		return out, nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func toResponseDtos(logs []entity.AuditLog) []models.AuditLogResponseDto {
+	out := make([]models.AuditLogResponseDto, 0, len(logs))
+	for _, l := range logs {
+		out = append(out, models.AuditLogResponseDto{
+			ID:         l.ID,
+			ActorID:    l.ActorID,
+			OwnerID:    l.OwnerID,
+			Action:     l.Action,
+			EntityType: l.EntityType,
+			EntityID:   l.EntityID,
+			Changes:    json.RawMessage(l.Changes),
+			RequestID:  l.RequestID,
+			CreatedAt:  l.CreatedAt,
+		})
+	}
+	return out
+}
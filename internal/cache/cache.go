@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Cache stores opaque values under string keys. Entries can carry tags so that every key
+// sharing a tag is dropped at once. Backends are best effort: a failing backend behaves
+// like an empty cache rather than failing the request.
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte, ttl time.Duration, tags ...string)
+	InvalidateTags(tags ...string)
+	// Epoch returns a counter that every InvalidateTags call advances. Read it before
+	// computing a value and pass it to SetIfUnchanged.
+	Epoch() uint64
+	// SetIfUnchanged stores the entry like Set, unless tags were invalidated since epoch was
+	// read, so that a value computed from data that changed meanwhile is never stored
+	SetIfUnchanged(key string, value []byte, ttl time.Duration, epoch uint64, tags ...string)
+}
+
+// Stats are the lookup counters of a cache since start
+type Stats struct {
+	Backend       string  `json:"backend"`
+	Hits          uint64  `json:"hits"`
+	Misses        uint64  `json:"misses"`
+	HitRatio      float64 `json:"hit_ratio"`
+	Invalidations uint64  `json:"invalidations"`
+}
+
+// Counting wraps a Cache and counts hits, misses and invalidated tags
+type Counting struct {
+	Cache
+	backend       string
+	hits          atomic.Uint64
+	misses        atomic.Uint64
+	invalidations atomic.Uint64
+}
+
+func NewCounting(c Cache, backend string) *Counting {
+	return &Counting{Cache: c, backend: backend}
+}
+
+func (c *Counting) Get(key string) ([]byte, bool) {
+	value, ok := c.Cache.Get(key)
+	if ok {
+		c.hits.Add(1)
+	} else {
+		c.misses.Add(1)
+	}
+	return value, ok
+}
+
+func (c *Counting) InvalidateTags(tags ...string) {
+	c.invalidations.Add(uint64(len(tags)))
+	c.Cache.InvalidateTags(tags...)
+}
+
+func (c *Counting) Stats() Stats {
+	s := Stats{
+		Backend:       c.backend,
+		Hits:          c.hits.Load(),
+		Misses:        c.misses.Load(),
+		Invalidations: c.invalidations.Load(),
+	}
+	if total := s.Hits + s.Misses; total > 0 {
+		s.HitRatio = float64(s.Hits) / float64(total)
+	}
+	return s
+}
@@ -0,0 +1,127 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+type memoryEntry struct {
+	value     []byte
+	expiresAt time.Time
+	tags      []string
+}
+
+// MemoryCache keeps entries in process. It is the default backend and is only correct for
+// a single instance; run several instances against RedisCache instead.
+type MemoryCache struct {
+	mu         sync.Mutex
+	entries    map[string]memoryEntry
+	tags       map[string]map[string]struct{} // tag -> keys
+	maxEntries int
+	epoch      uint64
+}
+
+// NewMemoryCache holds at most maxEntries keys; when full, expired entries are swept and,
+// failing that, the new entry is not stored
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	return &MemoryCache{
+		entries:    make(map[string]memoryEntry),
+		tags:       make(map[string]map[string]struct{}),
+		maxEntries: maxEntries,
+	}
+}
+
+func (m *MemoryCache) Get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(e.expiresAt) {
+		m.remove(key)
+		return nil, false
+	}
+	return e.value, true
+}
+
+func (m *MemoryCache) Set(key string, value []byte, ttl time.Duration, tags ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.set(key, value, ttl, tags)
+}
+
+func (m *MemoryCache) SetIfUnchanged(key string, value []byte, ttl time.Duration, epoch uint64, tags ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.epoch != epoch {
+		return
+	}
+	m.set(key, value, ttl, tags)
+}
+
+func (m *MemoryCache) Epoch() uint64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.epoch
+}
+
+// set stores the entry; the caller holds mu
+func (m *MemoryCache) set(key string, value []byte, ttl time.Duration, tags []string) {
+	if _, exists := m.entries[key]; exists {
+		m.remove(key)
+	} else if len(m.entries) >= m.maxEntries {
+		m.sweep()
+		if len(m.entries) >= m.maxEntries {
+			return
+		}
+	}
+	m.entries[key] = memoryEntry{value: value, expiresAt: time.Now().Add(ttl), tags: tags}
+	for _, tag := range tags {
+		keys, ok := m.tags[tag]
+		if !ok {
+			keys = make(map[string]struct{})
+			m.tags[tag] = keys
+		}
+		keys[key] = struct{}{}
+	}
+}
+
+func (m *MemoryCache) InvalidateTags(tags ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.epoch++
+	for _, tag := range tags {
+		for key := range m.tags[tag] {
+			m.remove(key)
+		}
+		delete(m.tags, tag)
+	}
+}
+
+// remove drops key and its tag index entries; the caller holds mu
+func (m *MemoryCache) remove(key string) {
+	e, ok := m.entries[key]
+	if !ok {
+		return
+	}
+	delete(m.entries, key)
+	for _, tag := range e.tags {
+		if keys, ok := m.tags[tag]; ok {
+			delete(keys, key)
+			if len(keys) == 0 {
+				delete(m.tags, tag)
+			}
+		}
+	}
+}
+
+// sweep drops every expired entry; the caller holds mu
+func (m *MemoryCache) sweep() {
+	now := time.Now()
+	for key, e := range m.entries {
+		if now.After(e.expiresAt) {
+			m.remove(key)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisTimeout bounds every call so a slow Redis degrades to cache misses, not slow requests
+const redisTimeout = 200 * time.Millisecond
+
+// errStaleEpoch aborts a conditional write after an invalidation
+var errStaleEpoch = errors.New("cache: invalidated since epoch")
+
+// RedisCache shares entries between instances. Each tag is a Redis set of the keys that
+// carry it; the set lives as long as the longest-lived key added to it. The epoch is a
+// counter key shared by all instances.
+type RedisCache struct {
+	Client *redis.Client
+	Prefix string
+}
+
+// NewRedisCache connects to url (redis://[:password@]host:port/db) and checks it is reachable
+func NewRedisCache(url, prefix string) (*RedisCache, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, err
+	}
+	client := redis.NewClient(opts)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, err
+	}
+	return &RedisCache{Client: client, Prefix: prefix}, nil
+}
+
+func (r *RedisCache) Get(key string) ([]byte, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+	value, err := r.Client.Get(ctx, r.Prefix+key).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			slog.Warn("cache: redis get failed", "key", key, "error", err)
+		}
+		return nil, false
+	}
+	return value, true
+}
+
+func (r *RedisCache) Set(key string, value []byte, ttl time.Duration, tags ...string) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+	_, err := r.Client.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		r.queueSet(ctx, p, key, value, ttl, tags)
+		return nil
+	})
+	if err != nil {
+		slog.Warn("cache: redis set failed", "key", key, "error", err)
+	}
+}
+
+// SetIfUnchanged watches the epoch key, so an invalidation that lands between the check
+// and the write aborts the write too
+func (r *RedisCache) SetIfUnchanged(key string, value []byte, ttl time.Duration, epoch uint64, tags ...string) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+	err := r.Client.Watch(ctx, func(tx *redis.Tx) error {
+		current, err := tx.Get(ctx, r.epochKey()).Uint64()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return err
+		}
+		if current != epoch {
+			return errStaleEpoch
+		}
+		_, err = tx.TxPipelined(ctx, func(p redis.Pipeliner) error {
+			r.queueSet(ctx, p, key, value, ttl, tags)
+			return nil
+		})
+		return err
+	}, r.epochKey())
+	if err != nil && !errors.Is(err, errStaleEpoch) && !errors.Is(err, redis.TxFailedErr) {
+		slog.Warn("cache: redis set failed", "key", key, "error", err)
+	}
+}
+
+// Epoch reads the shared counter. When Redis cannot be read it returns a value no write
+// will match, so nothing is cached from that computation.
+func (r *RedisCache) Epoch() uint64 {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+	epoch, err := r.Client.Get(ctx, r.epochKey()).Uint64()
+	if errors.Is(err, redis.Nil) {
+		return 0
+	}
+	if err != nil {
+		slog.Warn("cache: redis epoch read failed", "error", err)
+		return math.MaxUint64
+	}
+	return epoch
+}
+
+func (r *RedisCache) queueSet(ctx context.Context, p redis.Pipeliner, key string, value []byte, ttl time.Duration, tags []string) {
+	p.Set(ctx, r.Prefix+key, value, ttl)
+	for _, tag := range tags {
+		tagKey := r.tagKey(tag)
+		p.SAdd(ctx, tagKey, r.Prefix+key)
+		p.ExpireGT(ctx, tagKey, ttl)
+		p.ExpireNX(ctx, tagKey, ttl)
+	}
+}
+
+func (r *RedisCache) InvalidateTags(tags ...string) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+	// Advanced first, so a result computed before this call can no longer be stored
+	if err := r.Client.Incr(ctx, r.epochKey()).Err(); err != nil {
+		slog.Warn("cache: redis epoch advance failed", "error", err)
+	}
+	for _, tag := range tags {
+		tagKey := r.tagKey(tag)
+		keys, err := r.Client.SMembers(ctx, tagKey).Result()
+		if err != nil {
+			slog.Warn("cache: redis invalidate failed", "tag", tag, "error", err)
+			continue
+		}
+		if err := r.Client.Del(ctx, append(keys, tagKey)...).Err(); err != nil {
+			slog.Warn("cache: redis invalidate failed", "tag", tag, "error", err)
+		}
+	}
+}
+
+func (r *RedisCache) tagKey(tag string) string {
+	return r.Prefix + "tag:" + tag
+}
+
+func (r *RedisCache) epochKey() string {
+	return r.Prefix + "epoch"
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"Travel_Sync/internal/apperr"
+	"Travel_Sync/internal/calendar/service"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const icsContentType = "text/calendar; charset=utf-8"
+
+type CalendarHandler struct {
+	Svc *service.CalendarService
+}
+
+func NewCalendarHandler(svc *service.CalendarService) *CalendarHandler {
+	return &CalendarHandler{Svc: svc}
+}
+
+// GetTicketICS downloads a single ticket as an .ics file
+func (h *CalendarHandler) GetTicketICS(c *gin.Context) {
+	uid, ok := c.Get("user_id")
+	if !ok {
+		c.Error(apperr.ErrUnauthorized)
+		return
+	}
+	id, err := strconv.ParseInt(strings.TrimSuffix(c.Param("id"), ".ics"), 10, 64)
+	if err != nil || id <= 0 {
+		c.Error(apperr.ErrInvalidID)
+		return
+	}
+	body, err := h.Svc.TicketCalendar(c.Request.Context(), toInt64(uid), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="trip-%d.ics"`, id))
+	c.Data(http.StatusOK, icsContentType, []byte(body))
+}
+
+// GetFeed serves the public subscribable feed identified by its token
+func (h *CalendarHandler) GetFeed(c *gin.Context) {
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+	body, err := h.Svc.FeedCalendar(c.Request.Context(), token)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.Header("Cache-Control", "private, max-age=900")
+	c.Data(http.StatusOK, icsContentType, []byte(body))
+}
+
+// GetFeedURL returns the user's current feed link
+func (h *CalendarHandler) GetFeedURL(c *gin.Context) {
+	uid, ok := c.Get("user_id")
+	if !ok {
+		c.Error(apperr.ErrUnauthorized)
+		return
+	}
+	url, err := h.Svc.GetFeedURL(c.Request.Context(), toInt64(uid))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if url == "" {
+		c.Error(apperr.NotFound("feed_not_enabled", "calendar feed not enabled"))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{"feed_url": url}})
+}
+
+// RotateFeed issues a new feed link; the old one stops working immediately
+func (h *CalendarHandler) RotateFeed(c *gin.Context) {
+	uid, ok := c.Get("user_id")
+	if !ok {
+		c.Error(apperr.ErrUnauthorized)
+		return
+	}
+	url, err := h.Svc.RotateFeedToken(c.Request.Context(), toInt64(uid))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{"feed_url": url}})
+}
+
+// DisableFeed revokes the feed link
+func (h *CalendarHandler) DisableFeed(c *gin.Context) {
+	uid, ok := c.Get("user_id")
+	if !ok {
+		c.Error(apperr.ErrUnauthorized)
+		return
+	}
+	if err := h.Svc.DisableFeed(c.Request.Context(), toInt64(uid)); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": "calendar feed disabled"})
+}
+
+func toInt64(v interface{}) int64 {
+	if id, ok := v.(int64); ok {
+		return id
+	}
+	if f, ok := v.(float64); ok {
+		return int64(f)
+	}
+	return 0
+}
@@ -0,0 +1,95 @@
+package ics
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	dateTimeFormat = "20060102T150405Z"
+	maxLineOctets  = 75
+	prodID         = "-//Travel Sync//Travel Sync Calendar//EN"
+)
+
+// Event is a single VEVENT. Times are written in UTC.
+type Event struct {
+	UID          string
+	Summary      string
+	Description  string
+	Location     string
+	Start        time.Time
+	End          time.Time
+	LastModified time.Time
+}
+
+// Calendar is a VCALENDAR made up of events
+type Calendar struct {
+	Name   string
+	Events []Event
+}
+
+// Encode renders the calendar as RFC 5545 text with CRLF line endings and folded long lines
+func (c *Calendar) Encode() string {
+	var b strings.Builder
+	now := time.Now().UTC()
+
+	writeLine(&b, "BEGIN:VCALENDAR")
+	writeLine(&b, "VERSION:2.0")
+	writeLine(&b, "PRODID:"+prodID)
+	writeLine(&b, "CALSCALE:GREGORIAN")
+	writeLine(&b, "METHOD:PUBLISH")
+	if c.Name != "" {
+		writeLine(&b, "X-WR-CALNAME:"+escapeText(c.Name))
+	}
+	for _, e := range c.Events {
+		writeLine(&b, "BEGIN:VEVENT")
+		writeLine(&b, "UID:"+e.UID)
+		writeLine(&b, "DTSTAMP:"+now.Format(dateTimeFormat))
+		writeLine(&b, "DTSTART:"+e.Start.UTC().Format(dateTimeFormat))
+		writeLine(&b, "DTEND:"+e.End.UTC().Format(dateTimeFormat))
+		if !e.LastModified.IsZero() {
+			writeLine(&b, "LAST-MODIFIED:"+e.LastModified.UTC().Format(dateTimeFormat))
+		}
+		writeLine(&b, "SUMMARY:"+escapeText(e.Summary))
+		if e.Location != "" {
+			writeLine(&b, "LOCATION:"+escapeText(e.Location))
+		}
+		if e.Description != "" {
+			writeLine(&b, "DESCRIPTION:"+escapeText(e.Description))
+		}
+		writeLine(&b, "END:VEVENT")
+	}
+	writeLine(&b, "END:VCALENDAR")
+	return b.String()
+}
+
+// escapeText escapes TEXT property values per RFC 5545 section 3.3.11
+func escapeText(s string) string {
+	r := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	)
+	return r.Replace(s)
+}
+
+// writeLine folds the content line at 75 octets without splitting UTF-8 sequences
+func writeLine(b *strings.Builder, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// continuation lines start with a space which counts towards the limit
+		limit = maxLineOctets - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"Travel_Sync/internal/calendar/handler"
+	"Travel_Sync/internal/middleware"
+	"Travel_Sync/internal/security/config"
+	"Travel_Sync/internal/security/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterCalendarRoutes(router *gin.Engine, calendarHandler *handler.CalendarHandler, jwtService *service.JWTService) {
+	// Public feed: calendar apps cannot send our cookie, the token in the URL is the credential
+	router.GET("/calendar/:token", calendarHandler.GetFeed)
+
+	api := router.Group("/api")
+	{
+		calendar := api.Group("/calendar")
+		calendar.Use(config.JWTMiddleware(jwtService))
+		calendar.Use(middleware.GeneralRateLimiter())
+		{
+			calendar.GET("/tickets/:id", calendarHandler.GetTicketICS)
+			calendar.GET("/feed", calendarHandler.GetFeedURL)
+			calendar.POST("/feed/rotate", calendarHandler.RotateFeed)
+			calendar.DELETE("/feed", calendarHandler.DisableFeed)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"Travel_Sync/internal/apperr"
+	"Travel_Sync/internal/calendar/ics"
+	"Travel_Sync/internal/timezone"
+	tentity "Travel_Sync/internal/travel/entity"
+	tmodels "Travel_Sync/internal/travel/models"
+	trepo "Travel_Sync/internal/travel/repository"
+	urepo "Travel_Sync/internal/user/repository"
+	wrepo "Travel_Sync/internal/waitlist/repository"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// tripEventDuration is the length of the calendar event created for a trip
+const tripEventDuration = time.Hour
+
+type CalendarService struct {
+	TicketRepo   trepo.TravelTicketRepository
+	UserRepo     urepo.UserRepository
+	WaitlistRepo *wrepo.WaitlistRepo
+	BaseURL      string // public base URL of this API, used to build feed links
+}
+
+var (
+	ErrTicketNotFound = apperr.NotFound("ticket_not_found", "ticket not found")
+	ErrNotTicketOwner = apperr.Forbidden("not_ticket_owner", "forbidden")
+	ErrFeedNotFound   = apperr.NotFound("feed_not_found", "calendar feed not found")
+)
+
+func NewCalendarService(ticketRepo trepo.TravelTicketRepository, userRepo urepo.UserRepository, waitlistRepo *wrepo.WaitlistRepo, baseURL string) *CalendarService {
+	return &CalendarService{TicketRepo: ticketRepo, UserRepo: userRepo, WaitlistRepo: waitlistRepo, BaseURL: strings.TrimRight(baseURL, "/")}
+}
+
+// TicketCalendar returns an .ics document for a single ticket owned by the user
+func (s *CalendarService) TicketCalendar(ctx context.Context, currentUserID, ticketID int64) (string, error) {
+	ticket, err := s.TicketRepo.GetByID(ctx, ticketID)
+	if err != nil {
+		return "", apperr.NotFoundAs(err, ErrTicketNotFound)
+	}
+	if ticket.UserID != currentUserID {
+		return "", ErrNotTicketOwner
+	}
+	user, err := s.UserRepo.GetByID(ctx, currentUserID)
+	if err != nil {
+		return "", err
+	}
+	riders, err := s.coTravellers(ctx, []tentity.TravelTicket{*ticket})
+	if err != nil {
+		return "", err
+	}
+	cal := &ics.Calendar{Name: "Travel Sync trip", Events: []ics.Event{ticketEvent(ticket, timezone.Resolve(user.Timezone), riders[ticket.ID])}}
+	return cal.Encode(), nil
+}
+
+// FeedCalendar returns the subscribable calendar for the owner of the feed token
+func (s *CalendarService) FeedCalendar(ctx context.Context, token string) (string, error) {
+	if token == "" {
+		return "", ErrFeedNotFound
+	}
+	user, err := s.UserRepo.GetByCalendarToken(ctx, token)
+	if err != nil {
+		return "", apperr.NotFoundAs(err, ErrFeedNotFound)
+	}
+	loc := timezone.Resolve(user.Timezone)
+	tickets, err := s.TicketRepo.GetByUserID(ctx, user.ID)
+	if err != nil {
+		return "", err
+	}
+	riders, err := s.coTravellers(ctx, tickets)
+	if err != nil {
+		return "", err
+	}
+	cal := &ics.Calendar{Name: "Travel Sync trips", Events: make([]ics.Event, 0, len(tickets))}
+	for i := range tickets {
+		cal.Events = append(cal.Events, ticketEvent(&tickets[i], loc, riders[tickets[i].ID]))
+	}
+	return cal.Encode(), nil
+}
+
+// GetFeedURL returns the user's current feed URL, or an empty string when no feed is enabled
+func (s *CalendarService) GetFeedURL(ctx context.Context, userID int64) (string, error) {
+	user, err := s.UserRepo.GetByID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	if user.CalendarToken == nil {
+		return "", nil
+	}
+	return s.feedURL(*user.CalendarToken), nil
+}
+
+// RotateFeedToken issues a new feed token, invalidating any previous subscription link
+func (s *CalendarService) RotateFeedToken(ctx context.Context, userID int64) (string, error) {
+	token, err := generateToken()
+	if err != nil {
+		return "", err
+	}
+	if err := s.setToken(ctx, userID, &token); err != nil {
+		return "", err
+	}
+	return s.feedURL(token), nil
+}
+
+// DisableFeed revokes the user's feed token
+func (s *CalendarService) DisableFeed(ctx context.Context, userID int64) error {
+	return s.setToken(ctx, userID, nil)
+}
+
+func (s *CalendarService) setToken(ctx context.Context, userID int64, token *string) error {
+	user, err := s.UserRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	user.CalendarToken = token
+	_, err = s.UserRepo.UpdateUser(ctx, user)
+	return err
+}
+
+func (s *CalendarService) feedURL(token string) string {
+	return s.BaseURL + "/calendar/" + token + ".ics"
+}
+
+// coTravellers returns, per ticket, the names of the riders promoted onto it from the waitlist
+func (s *CalendarService) coTravellers(ctx context.Context, tickets []tentity.TravelTicket) (map[int64][]string, error) {
+	out := make(map[int64][]string)
+	if s.WaitlistRepo == nil || len(tickets) == 0 {
+		return out, nil
+	}
+	ticketIDs := make([]int64, 0, len(tickets))
+	for _, t := range tickets {
+		ticketIDs = append(ticketIDs, t.ID)
+	}
+	entries, err := s.WaitlistRepo.ListPromoted(ctx, ticketIDs)
+	if err != nil {
+		return nil, err
+	}
+	userIDs := make([]int64, 0, len(entries))
+	for _, e := range entries {
+		userIDs = append(userIDs, e.UserID)
+	}
+	users, err := s.UserRepo.GetByIDs(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[int64]string, len(users))
+	for _, u := range users {
+		names[u.ID] = u.Name
+	}
+	for _, e := range entries {
+		if name, ok := names[e.UserID]; ok && name != "" {
+			out[e.TicketID] = append(out[e.TicketID], name)
+		}
+	}
+	return out, nil
+}
+
+// ticketEvent describes a trip and who shares it; the departure in the description is shown
+// in loc, the reader's zone, while the event times stay absolute
+func ticketEvent(t *tentity.TravelTicket, loc *time.Location, coTravellers []string) ics.Event {
+	route := strings.Join(tmodels.Route(t.Source, t.Waypoints, t.Destination), " → ")
+	desc := []string{
+		"Route: " + route,
+		"Departure: " + t.DepartureAt.In(loc).Format("Mon 02 Jan 2006 15:04 MST") + " (" + loc.String() + ")",
+		fmt.Sprintf("Empty seats: %d", t.EmptySeats),
+		"Status: " + t.Status,
+	}
+	if len(coTravellers) > 0 {
+		desc = append(desc, "Co-travellers: "+strings.Join(coTravellers, ", "))
+	}
+	return ics.Event{
+		UID:          fmt.Sprintf("ticket-%d@travelsync.space", t.ID),
+		Summary:      "Trip: " + route,
+		Description:  strings.Join(desc, "\n"),
+		Location:     t.Source,
+		Start:        t.DepartureAt,
+		End:          t.DepartureAt.Add(tripEventDuration),
+		LastModified: t.UpdatedAt,
+	}
+}
+
+func generateToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -2,18 +2,58 @@ package config
 
 import (
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type AppConfig struct {
 	Port           string
 	PostgresURI    string
 	FrontendURL    string
+	PublicBaseURL  string
 	AllowedOrigins []string
 	CookieSecure   bool
 	//CookieDomain   string
 	GinMode        string
 	TrustedProxies []string
+	AdminEmails    []string
+
+	// DefaultTimezone is the campus zone used for users who have not set their own
+	DefaultTimezone string
+
+	// Soft-deleted rows are kept for SoftDeleteRetention, then hard-deleted by
+	// the purge job which runs every PurgeInterval
+	SoftDeleteRetention time.Duration
+	PurgeInterval       time.Duration
+
+	// Data exports are written to ExportDir and downloadable for ExportTTL
+	ExportDir string
+	ExportTTL time.Duration
+
+	// Recommendation score boosts for candidates from the same batch, for saved favourites
+	// and for riders the user has travelled with before; 0 turns a signal off
+	SameBatchWeight   float64
+	FavouriteWeight   float64
+	CoTravellerWeight float64
+
+	// Recommendation results are cached for RecommendationCacheTTL in CacheBackend: "memory"
+	// for a single instance, "redis" at RedisURL when running several, "none" to turn it off
+	CacheBackend           string
+	RedisURL               string
+	RecommendationCacheTTL time.Duration
+
+	// Every request's context, and so its database queries, is cancelled after QueryTimeout;
+	// QueryTimeouts overrides it per route, keyed by "METHOD /route/:param"
+	QueryTimeout  time.Duration
+	QueryTimeouts map[string]time.Duration
+
+	// LogLevel is the minimum level logged as JSON to stdout: debug, info, warn or error
+	LogLevel string
+
+	// MetricsToken, when set, is the bearer token Prometheus must send to scrape /metrics
+	MetricsToken string
 }
 
 func LoadConfig() *AppConfig {
@@ -30,11 +70,36 @@ func LoadConfig() *AppConfig {
 		Port:           os.Getenv("PORT"),
 		PostgresURI:    os.Getenv("POSTGRES_URI"),
 		FrontendURL:    os.Getenv("FRONTEND_URL"),
+		PublicBaseURL:  os.Getenv("PUBLIC_BASE_URL"),
 		AllowedOrigins: origins,
 		CookieSecure:   strings.ToLower(os.Getenv("COOKIE_SECURE")) == "true",
 		//CookieDomain:   os.Getenv("COOKIE_DOMAIN"),
 		GinMode:        os.Getenv("GIN_MODE"),
 		TrustedProxies: splitAndTrim(os.Getenv("TRUSTED_PROXIES")),
+		AdminEmails:    splitAndTrim(os.Getenv("ADMIN_EMAILS")),
+
+		DefaultTimezone: stringEnv("DEFAULT_TIMEZONE", "Asia/Kolkata"),
+
+		SoftDeleteRetention: time.Duration(intEnv("SOFT_DELETE_RETENTION_DAYS", 30)) * 24 * time.Hour,
+		PurgeInterval:       time.Duration(intEnv("PURGE_INTERVAL_MINUTES", 60)) * time.Minute,
+
+		ExportDir: stringEnv("EXPORT_DIR", filepath.Join(os.TempDir(), "travelsync-exports")),
+		ExportTTL: time.Duration(intEnv("EXPORT_TTL_HOURS", 168)) * time.Hour,
+
+		SameBatchWeight:   floatEnv("SCORE_SAME_BATCH_WEIGHT", 5),
+		FavouriteWeight:   floatEnv("SCORE_FAVOURITE_WEIGHT", 15),
+		CoTravellerWeight: floatEnv("SCORE_CO_TRAVELLER_WEIGHT", 10),
+
+		CacheBackend:           strings.ToLower(stringEnv("CACHE_BACKEND", "memory")),
+		RedisURL:               os.Getenv("REDIS_URL"),
+		RecommendationCacheTTL: time.Duration(intEnv("RECOMMENDATION_CACHE_TTL_SECONDS", 120)) * time.Second,
+
+		QueryTimeout:  time.Duration(intEnv("QUERY_TIMEOUT_MS", 5000)) * time.Millisecond,
+		QueryTimeouts: routeDurationsEnv("QUERY_TIMEOUT_OVERRIDES", defaultQueryTimeouts),
+
+		LogLevel: stringEnv("LOG_LEVEL", "info"),
+
+		MetricsToken: os.Getenv("METRICS_TOKEN"),
 	}
 
 }
@@ -52,3 +117,65 @@ func splitAndTrim(v string) []string {
 	}
 	return out
 }
+
+// defaultQueryTimeouts give recommendations a tighter budget and bulk imports a looser one
+const defaultQueryTimeouts = "GET /api/travel/:id/recommendations=3000," +
+	"POST /api/admin/flights/import=30000,POST /api/admin/trains/import=30000"
+
+// routeDurationsEnv reads "METHOD /route=ms" pairs separated by commas, e.g.
+// "GET /api/travel/:id/recommendations=3000", on top of the pairs in def: routes in the env
+// var replace their default, and other defaults stay. Malformed pairs are skipped.
+func routeDurationsEnv(key, def string) map[string]time.Duration {
+	out := make(map[string]time.Duration)
+	parseRouteDurations(def, out)
+	parseRouteDurations(os.Getenv(key), out)
+	return out
+}
+
+func parseRouteDurations(s string, out map[string]time.Duration) {
+	for _, pair := range splitAndTrim(s) {
+		route, ms, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(ms))
+		if err != nil || n <= 0 {
+			continue
+		}
+		out[strings.Join(strings.Fields(route), " ")] = time.Duration(n) * time.Millisecond
+	}
+}
+
+// stringEnv reads an env var, falling back to def when unset
+func stringEnv(key, def string) string {
+	if v := strings.TrimSpace(os.Getenv(key)); v != "" {
+		return v
+	}
+	return def
+}
+
+// intEnv reads a positive integer env var, falling back to def when unset or invalid
+func intEnv(key string, def int) int {
+	v := strings.TrimSpace(os.Getenv(key))
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		return def
+	}
+	return n
+}
+
+// floatEnv reads a non-negative number env var, falling back to def when unset or invalid
+func floatEnv(key string, def float64) float64 {
+	v := strings.TrimSpace(os.Getenv(key))
+	if v == "" {
+		return def
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || f < 0 {
+		return def
+	}
+	return f
+}
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS travel_tickets;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,28 @@
+-- Baseline: exactly the schema AutoMigrate created before versioned migrations. Every
+-- statement is IF NOT EXISTS so that databases created back then adopt this version
+-- without changes; everything added since has its own migration.
+
+CREATE TABLE IF NOT EXISTS users (
+    id           bigserial PRIMARY KEY,
+    name         varchar(255),
+    email        text NOT NULL,
+    batch        text NOT NULL,
+    phone_number varchar(10),
+    created_at   timestamptz,
+    updated_at   timestamptz,
+    CONSTRAINT uni_users_email UNIQUE (email)
+);
+
+CREATE TABLE IF NOT EXISTS travel_tickets (
+    id             bigserial PRIMARY KEY,
+    source         varchar(255) NOT NULL,
+    destination    varchar(255) NOT NULL,
+    empty_seats    bigint NOT NULL,
+    departure_at   timestamptz NOT NULL,
+    time_diff_mins bigint NOT NULL,
+    user_id        bigint NOT NULL,
+    phone_number   varchar(15) NOT NULL,
+    status         varchar(20) NOT NULL DEFAULT 'open',
+    created_at     timestamptz,
+    updated_at     timestamptz
+);
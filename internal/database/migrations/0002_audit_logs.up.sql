@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id          bigserial PRIMARY KEY,
+    actor_id    bigint NOT NULL,
+    owner_id    bigint NOT NULL,
+    action      varchar(32) NOT NULL,
+    entity_type varchar(32) NOT NULL,
+    entity_id   bigint NOT NULL,
+    changes     jsonb NOT NULL DEFAULT '{}',
+    request_id  varchar(64),
+    created_at  timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_actor_id ON audit_logs (actor_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_owner_id ON audit_logs (owner_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_entity ON audit_logs (entity_type, entity_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_created_at ON audit_logs (created_at);
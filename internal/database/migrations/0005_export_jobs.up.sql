@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS export_jobs (
+    id           bigserial PRIMARY KEY,
+    user_id      bigint NOT NULL,
+    status       varchar(20) NOT NULL DEFAULT 'pending',
+    file_path    varchar(512),
+    error        varchar(512),
+    created_at   timestamptz,
+    completed_at timestamptz,
+    expires_at   timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_export_jobs_user_id ON export_jobs (user_id);
+CREATE INDEX IF NOT EXISTS idx_export_jobs_expires_at ON export_jobs (expires_at);
@@ -0,0 +1,2 @@
+-- IANA zone; empty means the campus default
+ALTER TABLE users ADD COLUMN IF NOT EXISTS timezone varchar(64);
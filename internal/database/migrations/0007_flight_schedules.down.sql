@@ -0,0 +1,3 @@
+ALTER TABLE travel_tickets DROP COLUMN IF EXISTS flight_schedule_id;
+ALTER TABLE travel_tickets DROP COLUMN IF EXISTS flight_number;
+DROP TABLE IF EXISTS flight_schedules;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS flight_schedules (
+    id            bigserial PRIMARY KEY,
+    flight_number varchar(16) NOT NULL,
+    service_date  varchar(10) NOT NULL,
+    terminal      varchar(255) NOT NULL,
+    arrival_at    timestamptz,
+    departure_at  timestamptz,
+    created_at    timestamptz,
+    updated_at    timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_flight_schedules_number_date ON flight_schedules (flight_number, service_date);
+
+ALTER TABLE travel_tickets ADD COLUMN IF NOT EXISTS flight_number varchar(16);
+ALTER TABLE travel_tickets ADD COLUMN IF NOT EXISTS flight_schedule_id bigint;
+CREATE INDEX IF NOT EXISTS idx_travel_tickets_flight_schedule_id ON travel_tickets (flight_schedule_id);
@@ -0,0 +1,2 @@
+ALTER TABLE travel_tickets DROP COLUMN IF EXISTS train_number;
+DROP TABLE IF EXISTS train_timetables;
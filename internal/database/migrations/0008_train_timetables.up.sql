@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS train_timetables (
+    id             bigserial PRIMARY KEY,
+    train_number   varchar(16) NOT NULL,
+    station        varchar(255) NOT NULL,
+    scheduled_time varchar(5) NOT NULL,
+    created_at     timestamptz,
+    updated_at     timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_train_timetables_number_station ON train_timetables (train_number, station);
+
+ALTER TABLE travel_tickets ADD COLUMN IF NOT EXISTS train_number varchar(16);
@@ -0,0 +1,3 @@
+ALTER TABLE travel_tickets DROP COLUMN IF EXISTS party_size;
+ALTER TABLE travel_tickets DROP COLUMN IF EXISTS vehicle_type;
+ALTER TABLE travel_tickets DROP COLUMN IF EXISTS luggage_count;
@@ -0,0 +1,3 @@
+ALTER TABLE travel_tickets ADD COLUMN IF NOT EXISTS luggage_count bigint NOT NULL DEFAULT 0;
+ALTER TABLE travel_tickets ADD COLUMN IF NOT EXISTS vehicle_type varchar(16);
+ALTER TABLE travel_tickets ADD COLUMN IF NOT EXISTS party_size bigint NOT NULL DEFAULT 1;
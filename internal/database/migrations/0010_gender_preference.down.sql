@@ -0,0 +1,2 @@
+ALTER TABLE travel_tickets DROP COLUMN IF EXISTS gender_preference;
+ALTER TABLE users DROP COLUMN IF EXISTS gender;
@@ -0,0 +1,2 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS gender varchar(16);
+ALTER TABLE travel_tickets ADD COLUMN IF NOT EXISTS gender_preference varchar(16) NOT NULL DEFAULT 'any';
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS favourite_travellers (
+    id           bigserial PRIMARY KEY,
+    user_id      bigint NOT NULL,
+    favourite_id bigint NOT NULL,
+    created_at   timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_favourite_travellers_pair ON favourite_travellers (user_id, favourite_id);
+CREATE INDEX IF NOT EXISTS idx_favourite_travellers_favourite_id ON favourite_travellers (favourite_id);
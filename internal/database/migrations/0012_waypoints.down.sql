@@ -0,0 +1 @@
+ALTER TABLE travel_tickets DROP COLUMN IF EXISTS waypoints;
@@ -0,0 +1,2 @@
+-- Ordered intermediate stops between source and destination
+ALTER TABLE travel_tickets ADD COLUMN IF NOT EXISTS waypoints jsonb;
@@ -0,0 +1,3 @@
+ALTER TABLE travel_tickets DROP COLUMN IF EXISTS price_per_seat;
+ALTER TABLE travel_tickets DROP COLUMN IF EXISTS vehicle_details;
+ALTER TABLE travel_tickets DROP COLUMN IF EXISTS type;
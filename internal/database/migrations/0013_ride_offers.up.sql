@@ -0,0 +1,3 @@
+ALTER TABLE travel_tickets ADD COLUMN IF NOT EXISTS type varchar(16) NOT NULL DEFAULT 'share';
+ALTER TABLE travel_tickets ADD COLUMN IF NOT EXISTS vehicle_details varchar(255);
+ALTER TABLE travel_tickets ADD COLUMN IF NOT EXISTS price_per_seat bigint NOT NULL DEFAULT 0;
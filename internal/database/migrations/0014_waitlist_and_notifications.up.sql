@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS notifications (
+    id         bigserial PRIMARY KEY,
+    user_id    bigint NOT NULL,
+    kind       varchar(32) NOT NULL,
+    message    varchar(500) NOT NULL,
+    ticket_id  bigint,
+    read_at    timestamptz,
+    created_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_notifications_user_id ON notifications (user_id);
+
+CREATE TABLE IF NOT EXISTS waitlist_entries (
+    id          bigserial PRIMARY KEY,
+    ticket_id   bigint NOT NULL,
+    user_id     bigint NOT NULL,
+    status      varchar(20) NOT NULL DEFAULT 'waiting',
+    expires_at  timestamptz NOT NULL,
+    promoted_at timestamptz,
+    created_at  timestamptz,
+    updated_at  timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_waitlist_entries_ticket_id ON waitlist_entries (ticket_id);
+CREATE INDEX IF NOT EXISTS idx_waitlist_entries_user_id ON waitlist_entries (user_id);
+CREATE INDEX IF NOT EXISTS idx_waitlist_entries_expires_at ON waitlist_entries (expires_at);
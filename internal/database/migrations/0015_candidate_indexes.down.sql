@@ -0,0 +1,7 @@
+ALTER TABLE users ADD CONSTRAINT uni_users_email UNIQUE (email);
+DROP INDEX IF EXISTS idx_users_email;
+
+DROP INDEX IF EXISTS idx_travel_tickets_user_departure;
+DROP INDEX IF EXISTS idx_travel_tickets_open_waypoints;
+DROP INDEX IF EXISTS idx_travel_tickets_open_source_departure;
+DROP INDEX IF EXISTS idx_travel_tickets_open_destination_departure;
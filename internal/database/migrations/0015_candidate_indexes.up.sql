@@ -0,0 +1,25 @@
+-- Candidate lookups (GetCandidatesTimeWindowOutbound/Return) only ever want live, open
+-- tickets: match on the end point plus a departure range, or on a waypoint. Partial
+-- indexes keep closed and deleted tickets out of them entirely.
+CREATE INDEX IF NOT EXISTS idx_travel_tickets_open_destination_departure
+    ON travel_tickets (destination, departure_at)
+    WHERE status = 'open' AND deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_travel_tickets_open_source_departure
+    ON travel_tickets (source, departure_at)
+    WHERE status = 'open' AND deleted_at IS NULL;
+
+-- Serves the "waypoints @> '[stop]'" branch, which the planner BitmapOrs with the above
+CREATE INDEX IF NOT EXISTS idx_travel_tickets_open_waypoints
+    ON travel_tickets USING gin (waypoints jsonb_path_ops)
+    WHERE status = 'open' AND deleted_at IS NULL;
+
+-- Per-user lookups: ticket cap, one ticket per direction per date, "my tickets"
+CREATE INDEX IF NOT EXISTS idx_travel_tickets_user_departure
+    ON travel_tickets (user_id, departure_at)
+    WHERE deleted_at IS NULL;
+
+-- Email uniqueness as an index rather than the constraint AutoMigrate created; deleted
+-- users keep their email reserved so that signing in again restores them
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
+ALTER TABLE users DROP CONSTRAINT IF EXISTS uni_users_email;
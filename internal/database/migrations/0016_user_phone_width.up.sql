@@ -0,0 +1,2 @@
+-- Profiles accept the same phone numbers as tickets: an optional + and up to 14 digits
+ALTER TABLE users ALTER COLUMN phone_number TYPE varchar(15);
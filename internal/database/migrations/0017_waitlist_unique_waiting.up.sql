@@ -0,0 +1,10 @@
+-- A rider waits on a ticket at most once. Entries left over from concurrent joins keep the
+-- earliest in the queue and mark the rest as left.
+UPDATE waitlist_entries w SET status = 'left', updated_at = now()
+WHERE w.status = 'waiting' AND EXISTS (
+    SELECT 1 FROM waitlist_entries o
+    WHERE o.ticket_id = w.ticket_id AND o.user_id = w.user_id AND o.status = 'waiting' AND o.id < w.id
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_waitlist_entries_waiting
+    ON waitlist_entries (ticket_id, user_id) WHERE status = 'waiting';
@@ -2,16 +2,29 @@ package database
 
 import (
 	"Travel_Sync/internal/config"
-	tentity "Travel_Sync/internal/travel/entity"
-	"Travel_Sync/internal/user/entity"
-	"log"
+	"log/slog"
+	"time"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
 )
 
+// slowQueryThreshold is how long a query may take before it is logged as a warning
+const slowQueryThreshold = 200 * time.Millisecond
+
 func Connect(cfg *config.AppConfig) (*gorm.DB, error) {
-	db, err := gorm.Open(postgres.Open(cfg.PostgresURI), &gorm.Config{})
+	// Queries are logged through slog without their bound values, which hold personal data.
+	// Constraint violations come back as gorm errors, e.g. gorm.ErrDuplicatedKey.
+	db, err := gorm.Open(postgres.Open(cfg.PostgresURI), &gorm.Config{
+		TranslateError: true,
+		Logger: logger.NewSlogLogger(slog.Default(), logger.Config{
+			SlowThreshold:             slowQueryThreshold,
+			LogLevel:                  logger.Warn,
+			ParameterizedQueries:      true,
+			IgnoreRecordNotFoundError: true,
+		}),
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -23,7 +36,7 @@ func Connect(cfg *config.AppConfig) (*gorm.DB, error) {
 
 	// Set timezone to UTC for all database operations
 	if _, err := sqlDB.Exec("SET timezone = 'UTC'"); err != nil {
-		log.Printf("Warning: Failed to set timezone to UTC: %v", err)
+		slog.Warn("Failed to set timezone to UTC", "error", err)
 	}
 
 	// Test connection
@@ -31,25 +44,21 @@ func Connect(cfg *config.AppConfig) (*gorm.DB, error) {
 		return nil, err
 	}
 
-	// Automigrate schemas
-	if err := db.AutoMigrate(&tentity.TravelTicket{}, &entity.User{}); err != nil {
-		return nil, err
-	}
-
-	log.Println(" Connected to Postgres via GORM")
+	// The schema is managed by versioned migrations (see migrate.go), not AutoMigrate
+	slog.Info("Connected to Postgres via GORM")
 	return db, nil
 }
 
 func Disconnect(db *gorm.DB) {
 	sqlDB, err := db.DB()
 	if err != nil {
-		log.Println("Failed to get sql.DB for closing:", err)
+		slog.Error("Failed to get sql.DB for closing", "error", err)
 		return
 	}
 
 	if err := sqlDB.Close(); err != nil {
-		log.Println("Failed to close database connection:", err)
+		slog.Error("Failed to close database connection", "error", err)
 	} else {
-		log.Println("Database connection closed")
+		slog.Info("Database connection closed")
 	}
 }
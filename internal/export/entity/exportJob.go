@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+type ExportJob struct {
+	ID          int64      `gorm:"primaryKey;autoIncrement;not null" json:"id"`
+	UserID      int64      `gorm:"not null;index" json:"-"`
+	Status      string     `gorm:"type:varchar(20);not null;default:pending" json:"status"`
+	FilePath    string     `gorm:"size:512" json:"-"`
+	Error       string     `gorm:"size:512" json:"error,omitempty"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time `gorm:"index" json:"expires_at,omitempty"`
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"Travel_Sync/internal/apperr"
+	"Travel_Sync/internal/export/service"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ExportHandler struct {
+	Svc *service.ExportService
+}
+
+func NewExportHandler(svc *service.ExportService) *ExportHandler {
+	return &ExportHandler{Svc: svc}
+}
+
+// RequestExport starts building an archive of the user's data
+func (h *ExportHandler) RequestExport(c *gin.Context) {
+	uid, ok := c.Get("user_id")
+	if !ok {
+		c.Error(apperr.ErrUnauthorized)
+		return
+	}
+	job, err := h.Svc.RequestExport(c.Request.Context(), toInt64(uid))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"success": true, "data": job})
+}
+
+// GetJobs lists the user's exports
+func (h *ExportHandler) GetJobs(c *gin.Context) {
+	uid, ok := c.Get("user_id")
+	if !ok {
+		c.Error(apperr.ErrUnauthorized)
+		return
+	}
+	jobs, err := h.Svc.GetJobs(c.Request.Context(), toInt64(uid))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": jobs})
+}
+
+// GetJob returns the status of an export and, once ready, its download link
+func (h *ExportHandler) GetJob(c *gin.Context) {
+	uid, ok := c.Get("user_id")
+	if !ok {
+		c.Error(apperr.ErrUnauthorized)
+		return
+	}
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	job, err := h.Svc.GetJob(c.Request.Context(), toInt64(uid), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": job})
+}
+
+// Download streams a finished archive
+func (h *ExportHandler) Download(c *gin.Context) {
+	uid, ok := c.Get("user_id")
+	if !ok {
+		c.Error(apperr.ErrUnauthorized)
+		return
+	}
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	path, err := h.Svc.GetArchivePath(c.Request.Context(), toInt64(uid), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.FileAttachment(path, fmt.Sprintf("travelsync-export-%d.zip", id))
+}
+
+func parseID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.Error(apperr.ErrInvalidID)
+		return 0, false
+	}
+	return id, true
+}
+
+func toInt64(v interface{}) int64 {
+	if id, ok := v.(int64); ok {
+		return id
+	}
+	if f, ok := v.(float64); ok {
+		return int64(f)
+	}
+	return 0
+}
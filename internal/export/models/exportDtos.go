@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// Export job statuses
+const (
+	StatusPending = "pending"
+	StatusRunning = "running"
+	StatusReady   = "ready"
+	StatusFailed  = "failed"
+)
+
+type ExportJobResponseDto struct {
+	ID          int64      `json:"id"`
+	Status      string     `json:"status"`
+	Error       string     `json:"error,omitempty"`
+	DownloadURL string     `json:"download_url,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+}
+
+// ProfileExport is the user's profile as written to the archive
+type ProfileExport struct {
+	ID          int64     `json:"id"`
+	Name        string    `json:"name"`
+	Email       string    `json:"email"`
+	Batch       string    `json:"batch"`
+	PhoneNumber string    `json:"phone_number"`
+	Timezone    string    `json:"timezone"`
+	Gender      string    `json:"gender"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	CalendarFeedEnabled bool `json:"calendar_feed_enabled"` // the feed token itself is a credential and left out
+}
+
+// FavouriteExport is a traveller the user saved as a favourite. Name, email and batch are
+// empty when that account has been deleted.
+type FavouriteExport struct {
+	UserID  int64     `json:"user_id"`
+	Name    string    `json:"name"`
+	Email   string    `json:"email"`
+	Batch   string    `json:"batch"`
+	AddedAt time.Time `json:"added_at"`
+}
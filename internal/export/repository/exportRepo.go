@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"Travel_Sync/internal/export/entity"
+	"Travel_Sync/internal/export/models"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ExportRepo struct {
+	DB *gorm.DB
+}
+
+func NewExportRepo(db *gorm.DB) *ExportRepo {
+	return &ExportRepo{DB: db}
+}
+
+func (r *ExportRepo) Create(ctx context.Context, job *entity.ExportJob) (*entity.ExportJob, error) {
+	if err := r.DB.WithContext(ctx).Create(job).Error; err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (r *ExportRepo) GetByID(ctx context.Context, id int64) (*entity.ExportJob, error) {
+	var job entity.ExportJob
+	if err := r.DB.WithContext(ctx).First(&job, id).Error; err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *ExportRepo) Update(ctx context.Context, job *entity.ExportJob) error {
+	return r.DB.WithContext(ctx).Save(job).Error
+}
+
+func (r *ExportRepo) Delete(ctx context.Context, id int64) error {
+	return r.DB.WithContext(ctx).Delete(&entity.ExportJob{ID: id}).Error
+}
+
+// GetByUserID returns the user's export jobs, newest first
+func (r *ExportRepo) GetByUserID(ctx context.Context, userID int64) ([]entity.ExportJob, error) {
+	var jobs []entity.ExportJob
+	err := r.DB.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").Find(&jobs).Error
+	return jobs, err
+}
+
+// GetInFlightByUserID returns the user's pending or running job, if any
+func (r *ExportRepo) GetInFlightByUserID(ctx context.Context, userID int64) (*entity.ExportJob, error) {
+	var job entity.ExportJob
+	err := r.DB.WithContext(ctx).Where("user_id = ? AND status IN ?", userID, []string{models.StatusPending, models.StatusRunning}).
+		First(&job).Error
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// GetExpired returns jobs whose archive expired before now
+func (r *ExportRepo) GetExpired(ctx context.Context, now time.Time) ([]entity.ExportJob, error) {
+	var jobs []entity.ExportJob
+	err := r.DB.WithContext(ctx).Where("expires_at IS NOT NULL AND expires_at < ?", now).Find(&jobs).Error
+	return jobs, err
+}
+
+// FailInFlight marks jobs left pending or running (e.g. by a restart) as failed
+func (r *ExportRepo) FailInFlight(ctx context.Context, reason string) (int64, error) {
+	res := r.DB.WithContext(ctx).Model(&entity.ExportJob{}).
+		Where("status IN ?", []string{models.StatusPending, models.StatusRunning}).
+		Updates(map[string]interface{}{"status": models.StatusFailed, "error": reason})
+	return res.RowsAffected, res.Error
+}
@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"Travel_Sync/internal/export/handler"
+	"Travel_Sync/internal/middleware"
+	"Travel_Sync/internal/security/config"
+	"Travel_Sync/internal/security/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterExportRoutes(router *gin.Engine, exportHandler *handler.ExportHandler, jwtService *service.JWTService) {
+	api := router.Group("/api")
+	{
+		export := api.Group("/export")
+		export.Use(config.JWTMiddleware(jwtService))
+		export.Use(middleware.GeneralRateLimiter())
+		{
+			export.POST("", exportHandler.RequestExport)
+			export.GET("", exportHandler.GetJobs)
+			export.GET("/:id", exportHandler.GetJob)
+			export.GET("/:id/download", exportHandler.Download)
+		}
+	}
+}
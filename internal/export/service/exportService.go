@@ -0,0 +1,383 @@
+package service
+
+import (
+	"Travel_Sync/internal/apperr"
+	arepo "Travel_Sync/internal/audit/repository"
+	"Travel_Sync/internal/export/entity"
+	"Travel_Sync/internal/export/models"
+	"Travel_Sync/internal/export/repository"
+	nrepo "Travel_Sync/internal/notification/repository"
+	trepo "Travel_Sync/internal/travel/repository"
+	urepo "Travel_Sync/internal/user/repository"
+	wrepo "Travel_Sync/internal/waitlist/repository"
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const timeFormat = time.RFC3339
+
+var (
+	ErrExportNotFound = apperr.NotFound("export_not_found", "export not found")
+	ErrNotExportOwner = apperr.Forbidden("not_export_owner", "forbidden")
+	ErrExportNotReady = apperr.Conflict("export_not_ready", "export is not ready")
+)
+
+type ExportService struct {
+	Repo             *repository.ExportRepo
+	UserRepo         urepo.UserRepository
+	TicketRepo       trepo.TravelTicketRepository
+	AuditRepo        *arepo.AuditRepo
+	FavouriteRepo    urepo.FavouriteRepository
+	WaitlistRepo     *wrepo.WaitlistRepo
+	NotificationRepo *nrepo.NotificationRepo
+	Dir              string        // where archives are written
+	TTL              time.Duration // how long a finished archive stays downloadable
+}
+
+func NewExportService(repo *repository.ExportRepo, userRepo urepo.UserRepository, ticketRepo trepo.TravelTicketRepository, auditRepo *arepo.AuditRepo, favouriteRepo urepo.FavouriteRepository, waitlistRepo *wrepo.WaitlistRepo, notificationRepo *nrepo.NotificationRepo, dir string, ttl time.Duration) *ExportService {
+	return &ExportService{Repo: repo, UserRepo: userRepo, TicketRepo: ticketRepo, AuditRepo: auditRepo, FavouriteRepo: favouriteRepo, WaitlistRepo: waitlistRepo, NotificationRepo: notificationRepo, Dir: dir, TTL: ttl}
+}
+
+// RequestExport queues a new export for the user and builds it in the background.
+// If the user already has an export in flight, that job is returned instead.
+func (s *ExportService) RequestExport(ctx context.Context, userID int64) (*models.ExportJobResponseDto, error) {
+	if job, err := s.Repo.GetInFlightByUserID(ctx, userID); err == nil {
+		return toResponseDto(job), nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	job, err := s.Repo.Create(ctx, &entity.ExportJob{UserID: userID, Status: models.StatusPending})
+	if err != nil {
+		return nil, err
+	}
+	// The archive is built after the request has finished, so only its values are kept
+	go s.run(context.WithoutCancel(ctx), job)
+	return toResponseDto(job), nil
+}
+
+// GetJob returns the status of one of the user's export jobs
+func (s *ExportService) GetJob(ctx context.Context, currentUserID, jobID int64) (*models.ExportJobResponseDto, error) {
+	job, err := s.getOwnedJob(ctx, currentUserID, jobID)
+	if err != nil {
+		return nil, err
+	}
+	return toResponseDto(job), nil
+}
+
+// GetJobs lists the user's export jobs
+func (s *ExportService) GetJobs(ctx context.Context, userID int64) ([]*models.ExportJobResponseDto, error) {
+	jobs, err := s.Repo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*models.ExportJobResponseDto, 0, len(jobs))
+	for i := range jobs {
+		out = append(out, toResponseDto(&jobs[i]))
+	}
+	return out, nil
+}
+
+// GetArchivePath returns the file of a finished export owned by the user
+func (s *ExportService) GetArchivePath(ctx context.Context, currentUserID, jobID int64) (string, error) {
+	job, err := s.getOwnedJob(ctx, currentUserID, jobID)
+	if err != nil {
+		return "", err
+	}
+	if job.Status != models.StatusReady {
+		return "", ErrExportNotReady
+	}
+	return job.FilePath, nil
+}
+
+// CleanupExpired removes archives past their expiry along with their jobs
+func (s *ExportService) CleanupExpired(ctx context.Context) (int, error) {
+	jobs, err := s.Repo.GetExpired(ctx, time.Now().UTC())
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	for _, job := range jobs {
+		if job.FilePath != "" {
+			if err := os.Remove(job.FilePath); err != nil && !os.IsNotExist(err) {
+				slog.ErrorContext(ctx, "export: failed to remove archive", "path", job.FilePath, "error", err)
+				continue
+			}
+		}
+		if err := s.Repo.Delete(ctx, job.ID); err != nil {
+			slog.ErrorContext(ctx, "export: failed to delete job", "job_id", job.ID, "error", err)
+			continue
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// FailInterrupted marks jobs that were in flight when the process stopped as failed
+func (s *ExportService) FailInterrupted(ctx context.Context) error {
+	_, err := s.Repo.FailInFlight(ctx, "interrupted, please request a new export")
+	return err
+}
+
+func (s *ExportService) getOwnedJob(ctx context.Context, currentUserID, jobID int64) (*entity.ExportJob, error) {
+	job, err := s.Repo.GetByID(ctx, jobID)
+	if err != nil {
+		return nil, apperr.NotFoundAs(err, ErrExportNotFound)
+	}
+	if job.UserID != currentUserID {
+		return nil, ErrNotExportOwner
+	}
+	return job, nil
+}
+
+func (s *ExportService) run(ctx context.Context, job *entity.ExportJob) {
+	job.Status = models.StatusRunning
+	if err := s.Repo.Update(ctx, job); err != nil {
+		slog.ErrorContext(ctx, "export: failed to mark job running", "job_id", job.ID, "error", err)
+	}
+
+	path, err := s.buildArchive(ctx, job)
+	now := time.Now().UTC()
+	job.CompletedAt = &now
+	if err != nil {
+		slog.ErrorContext(ctx, "export: job failed", "job_id", job.ID, "error", err)
+		job.Status = models.StatusFailed
+		job.Error = "export failed, please try again"
+	} else {
+		expires := now.Add(s.TTL)
+		job.Status = models.StatusReady
+		job.FilePath = path
+		job.ExpiresAt = &expires
+	}
+	if err := s.Repo.Update(ctx, job); err != nil {
+		slog.ErrorContext(ctx, "export: failed to save job", "job_id", job.ID, "error", err)
+	}
+}
+
+// buildArchive writes a zip with every dataset tied to the user in both JSON and CSV
+func (s *ExportService) buildArchive(ctx context.Context, job *entity.ExportJob) (string, error) {
+	user, err := s.UserRepo.GetByID(ctx, job.UserID)
+	if err != nil {
+		return "", fmt.Errorf("load user: %w", err)
+	}
+	tickets, err := s.TicketRepo.GetByUserIDIncludingDeleted(ctx, job.UserID)
+	if err != nil {
+		return "", fmt.Errorf("load tickets: %w", err)
+	}
+	auditLogs, err := s.AuditRepo.ListAllForUser(ctx, job.UserID)
+	if err != nil {
+		return "", fmt.Errorf("load audit log: %w", err)
+	}
+	favourites, err := s.favourites(ctx, job.UserID)
+	if err != nil {
+		return "", fmt.Errorf("load favourites: %w", err)
+	}
+	waitlist, err := s.WaitlistRepo.ListByUserID(ctx, job.UserID)
+	if err != nil {
+		return "", fmt.Errorf("load waitlist entries: %w", err)
+	}
+	notifications, err := s.NotificationRepo.ListAllForUser(ctx, job.UserID)
+	if err != nil {
+		return "", fmt.Errorf("load notifications: %w", err)
+	}
+
+	if err := os.MkdirAll(s.Dir, 0o700); err != nil {
+		return "", err
+	}
+	path := filepath.Join(s.Dir, fmt.Sprintf("export-%d-%d.zip", job.UserID, job.ID))
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return "", err
+	}
+	zw := zip.NewWriter(f)
+
+	profile := models.ProfileExport{
+		ID:          user.ID,
+		Name:        user.Name,
+		Email:       user.Email,
+		Batch:       user.Batch,
+		PhoneNumber: user.PhoneNumber,
+		Timezone:    user.Timezone,
+		Gender:      user.Gender,
+		CreatedAt:   user.CreatedAt,
+		UpdatedAt:   user.UpdatedAt,
+
+		CalendarFeedEnabled: user.CalendarToken != nil,
+	}
+	profileRows := [][]string{
+		{"id", "name", "email", "batch", "phone_number", "timezone", "gender", "calendar_feed_enabled", "created_at", "updated_at"},
+		{i64(profile.ID), profile.Name, profile.Email, profile.Batch, profile.PhoneNumber, profile.Timezone, profile.Gender,
+			strconv.FormatBool(profile.CalendarFeedEnabled), profile.CreatedAt.UTC().Format(timeFormat), profile.UpdatedAt.UTC().Format(timeFormat)},
+	}
+
+	ticketRows := [][]string{{"id", "source", "destination", "waypoints", "empty_seats", "departure_at", "time_diff_mins",
+		"phone_number", "status", "type", "vehicle_type", "vehicle_details", "price_per_seat", "party_size", "luggage_count",
+		"gender_preference", "flight_number", "flight_schedule_id", "train_number", "created_at", "updated_at", "deleted_at"}}
+	for _, t := range tickets {
+		deletedAt := ""
+		if t.DeletedAt.Valid {
+			deletedAt = t.DeletedAt.Time.UTC().Format(timeFormat)
+		}
+		scheduleID := ""
+		if t.FlightScheduleID != nil {
+			scheduleID = i64(*t.FlightScheduleID)
+		}
+		ticketRows = append(ticketRows, []string{
+			i64(t.ID), t.Source, t.Destination, strings.Join(t.Waypoints, "|"), strconv.Itoa(t.EmptySeats), t.DepartureAt.UTC().Format(timeFormat),
+			strconv.Itoa(t.TimeDiffMins), t.PhoneNumber, t.Status, t.Type, t.VehicleType, t.VehicleDetails,
+			strconv.Itoa(t.PricePerSeat), strconv.Itoa(t.PartySize), strconv.Itoa(t.LuggageCount),
+			t.GenderPreference, t.FlightNumber, scheduleID, t.TrainNumber, t.CreatedAt.UTC().Format(timeFormat),
+			t.UpdatedAt.UTC().Format(timeFormat), deletedAt,
+		})
+	}
+
+	favouriteRows := [][]string{{"user_id", "name", "email", "batch", "added_at"}}
+	for _, f := range favourites {
+		favouriteRows = append(favouriteRows, []string{i64(f.UserID), f.Name, f.Email, f.Batch, f.AddedAt.UTC().Format(timeFormat)})
+	}
+
+	waitlistRows := [][]string{{"id", "ticket_id", "status", "expires_at", "promoted_at", "created_at", "updated_at"}}
+	for _, e := range waitlist {
+		waitlistRows = append(waitlistRows, []string{
+			i64(e.ID), i64(e.TicketID), e.Status, e.ExpiresAt.UTC().Format(timeFormat), optTime(e.PromotedAt),
+			e.CreatedAt.UTC().Format(timeFormat), e.UpdatedAt.UTC().Format(timeFormat),
+		})
+	}
+
+	notificationRows := [][]string{{"id", "kind", "message", "ticket_id", "read_at", "created_at"}}
+	for _, n := range notifications {
+		ticketID := ""
+		if n.TicketID != nil {
+			ticketID = i64(*n.TicketID)
+		}
+		notificationRows = append(notificationRows, []string{
+			i64(n.ID), n.Kind, n.Message, ticketID, optTime(n.ReadAt), n.CreatedAt.UTC().Format(timeFormat),
+		})
+	}
+
+	auditRows := [][]string{{"id", "actor_id", "owner_id", "action", "entity_type", "entity_id", "changes", "request_id", "created_at"}}
+	for _, a := range auditLogs {
+		auditRows = append(auditRows, []string{
+			i64(a.ID), i64(a.ActorID), i64(a.OwnerID), a.Action, a.EntityType, i64(a.EntityID),
+			a.Changes, a.RequestID, a.CreatedAt.UTC().Format(timeFormat),
+		})
+	}
+
+	writeErr := firstErr(
+		writeJSON(zw, "profile.json", profile),
+		writeCSV(zw, "profile.csv", profileRows),
+		writeJSON(zw, "tickets.json", tickets),
+		writeCSV(zw, "tickets.csv", ticketRows),
+		writeJSON(zw, "audit_log.json", auditLogs),
+		writeCSV(zw, "audit_log.csv", auditRows),
+		writeJSON(zw, "favourites.json", favourites),
+		writeCSV(zw, "favourites.csv", favouriteRows),
+		writeJSON(zw, "waitlist.json", waitlist),
+		writeCSV(zw, "waitlist.csv", waitlistRows),
+		writeJSON(zw, "notifications.json", notifications),
+		writeCSV(zw, "notifications.csv", notificationRows),
+	)
+	closeErr := firstErr(zw.Close(), f.Close())
+	if err := firstErr(writeErr, closeErr); err != nil {
+		_ = os.Remove(path)
+		return "", err
+	}
+	return path, nil
+}
+
+// favourites returns the user's saved favourites with the details of accounts that still exist
+func (s *ExportService) favourites(ctx context.Context, userID int64) ([]models.FavouriteExport, error) {
+	favs, err := s.FavouriteRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int64, 0, len(favs))
+	for _, f := range favs {
+		ids = append(ids, f.FavouriteID)
+	}
+	users, err := s.UserRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]models.FavouriteExport, 0, len(favs))
+	for _, f := range favs {
+		fav := models.FavouriteExport{UserID: f.FavouriteID, AddedAt: f.CreatedAt}
+		for _, u := range users {
+			if u.ID == f.FavouriteID {
+				fav.Name, fav.Email, fav.Batch = u.Name, u.Email, u.Batch
+				break
+			}
+		}
+		out = append(out, fav)
+	}
+	return out, nil
+}
+
+func writeJSON(zw *zip.Writer, name string, v interface{}) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func writeCSV(zw *zip.Writer, name string, rows [][]string) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+func firstErr(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func i64(v int64) string {
+	return strconv.FormatInt(v, 10)
+}
+
+func optTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(timeFormat)
+}
+
+func toResponseDto(job *entity.ExportJob) *models.ExportJobResponseDto {
+	dto := &models.ExportJobResponseDto{
+		ID:          job.ID,
+		Status:      job.Status,
+		Error:       job.Error,
+		CreatedAt:   job.CreatedAt,
+		CompletedAt: job.CompletedAt,
+		ExpiresAt:   job.ExpiresAt,
+	}
+	if job.Status == models.StatusReady {
+		dto.DownloadURL = fmt.Sprintf("/api/export/%d/download", job.ID)
+	}
+	return dto
+}
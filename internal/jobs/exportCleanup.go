@@ -0,0 +1,46 @@
+package jobs
+
+import (
+	exportService "Travel_Sync/internal/export/service"
+	"context"
+	"log/slog"
+	"time"
+)
+
+// ExportCleanupJob deletes data export archives once their download window has passed
+type ExportCleanupJob struct {
+	Svc      *exportService.ExportService
+	Interval time.Duration
+}
+
+func NewExportCleanupJob(svc *exportService.ExportService, interval time.Duration) *ExportCleanupJob {
+	return &ExportCleanupJob{Svc: svc, Interval: interval}
+}
+
+// Start runs the cleanup once immediately and then every Interval until ctx is cancelled
+func (j *ExportCleanupJob) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(j.Interval)
+		defer ticker.Stop()
+		for {
+			j.RunOnce(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// RunOnce performs a single cleanup pass
+func (j *ExportCleanupJob) RunOnce(ctx context.Context) {
+	n, err := j.Svc.CleanupExpired(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "export cleanup failed", "error", err)
+		return
+	}
+	if n > 0 {
+		slog.InfoContext(ctx, "export cleanup: removed expired exports", "count", n)
+	}
+}
@@ -0,0 +1,82 @@
+package jobs
+
+import (
+	notificationRepo "Travel_Sync/internal/notification/repository"
+	travelRepo "Travel_Sync/internal/travel/repository"
+	userRepo "Travel_Sync/internal/user/repository"
+	waitlistRepo "Travel_Sync/internal/waitlist/repository"
+	"context"
+	"log/slog"
+	"time"
+)
+
+// PurgeJob hard-deletes soft-deleted tickets and users once the retention window has passed
+type PurgeJob struct {
+	TicketRepo       travelRepo.TravelTicketRepository
+	UserRepo         userRepo.UserRepository
+	WaitlistRepo     *waitlistRepo.WaitlistRepo
+	NotificationRepo *notificationRepo.NotificationRepo
+	Retention        time.Duration
+	Interval         time.Duration
+}
+
+func NewPurgeJob(ticketRepo travelRepo.TravelTicketRepository, userRepo userRepo.UserRepository, waitlistRepo *waitlistRepo.WaitlistRepo, notificationRepo *notificationRepo.NotificationRepo, retention, interval time.Duration) *PurgeJob {
+	return &PurgeJob{TicketRepo: ticketRepo, UserRepo: userRepo, WaitlistRepo: waitlistRepo, NotificationRepo: notificationRepo, Retention: retention, Interval: interval}
+}
+
+// Start runs the purge once immediately and then every Interval until ctx is cancelled
+func (j *PurgeJob) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(j.Interval)
+		defer ticker.Stop()
+		for {
+			j.RunOnce(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// RunOnce performs a single purge pass
+func (j *PurgeJob) RunOnce(ctx context.Context) {
+	cutoff := time.Now().UTC().Add(-j.Retention)
+
+	// Users first, taking all of their tickets with them so nothing is left orphaned
+	userIDs, err := j.UserRepo.GetIDsDeletedBefore(ctx, cutoff)
+	if err != nil {
+		slog.ErrorContext(ctx, "purge: failed to list deleted users", "error", err)
+		return
+	}
+	if len(userIDs) > 0 {
+		if err := j.WaitlistRepo.DeleteByUserIDs(ctx, userIDs); err != nil {
+			slog.ErrorContext(ctx, "purge: failed to purge waitlist entries of deleted users", "error", err)
+			return
+		}
+		if err := j.NotificationRepo.DeleteByUserIDs(ctx, userIDs); err != nil {
+			slog.ErrorContext(ctx, "purge: failed to purge notifications of deleted users", "error", err)
+			return
+		}
+		if _, err := j.TicketRepo.PurgeByUserIDs(ctx, userIDs); err != nil {
+			slog.ErrorContext(ctx, "purge: failed to purge tickets of deleted users", "error", err)
+			return
+		}
+		n, err := j.UserRepo.PurgeByIDs(ctx, userIDs)
+		if err != nil {
+			slog.ErrorContext(ctx, "purge: failed to purge users", "error", err)
+			return
+		}
+		slog.InfoContext(ctx, "purge: removed users", "count", n)
+	}
+
+	n, err := j.TicketRepo.PurgeDeletedBefore(ctx, cutoff)
+	if err != nil {
+		slog.ErrorContext(ctx, "purge: failed to purge tickets", "error", err)
+		return
+	}
+	if n > 0 {
+		slog.InfoContext(ctx, "purge: removed tickets", "count", n)
+	}
+}
@@ -0,0 +1,46 @@
+package jobs
+
+import (
+	waitlistService "Travel_Sync/internal/waitlist/service"
+	"context"
+	"log/slog"
+	"time"
+)
+
+// WaitlistExpiryJob expires waitlist entries whose trip has departed
+type WaitlistExpiryJob struct {
+	Svc      *waitlistService.WaitlistService
+	Interval time.Duration
+}
+
+func NewWaitlistExpiryJob(svc *waitlistService.WaitlistService, interval time.Duration) *WaitlistExpiryJob {
+	return &WaitlistExpiryJob{Svc: svc, Interval: interval}
+}
+
+// Start runs the expiry once immediately and then every Interval until ctx is cancelled
+func (j *WaitlistExpiryJob) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(j.Interval)
+		defer ticker.Stop()
+		for {
+			j.RunOnce(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// RunOnce performs a single expiry pass
+func (j *WaitlistExpiryJob) RunOnce(ctx context.Context) {
+	n, err := j.Svc.ExpireStale(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "waitlist expiry failed", "error", err)
+		return
+	}
+	if n > 0 {
+		slog.InfoContext(ctx, "waitlist expiry: expired entries", "count", n)
+	}
+}
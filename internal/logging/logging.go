@@ -0,0 +1,129 @@
+// Package logging sets up the app's structured JSON logger. Every record is scrubbed of
+// secrets, tokens and phone numbers, and records logged with a request's context carry its
+// request ID.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+// Redacted replaces sensitive values in logs
+const Redacted = "[REDACTED]"
+
+// sensitiveKeys are substrings of attribute keys whose values are never logged
+var sensitiveKeys = []string{"token", "secret", "password", "authorization", "cookie", "phone", "api_key", "apikey"}
+
+// sensitivePatterns catch secrets that end up inside messages and error strings
+var sensitivePatterns = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	// Authorization header values
+	{regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9._~+/=-]+`), "Bearer " + Redacted},
+	// JWTs, including the app's own session token
+	{regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`), Redacted},
+	// Google OAuth access and refresh tokens
+	{regexp.MustCompile(`\bya29\.[A-Za-z0-9._-]+`), Redacted},
+	{regexp.MustCompile(`\b1//[A-Za-z0-9._-]+`), Redacted},
+	// key=value pairs in URLs, DSNs and error strings, e.g. password=... or refresh_token=...
+	{regexp.MustCompile(`(?i)\b([a-z_]*(?:token|secret|password)[a-z_]*)=[^&\s"]+`), "${1}=" + Redacted},
+	// Phone numbers: an optional + and 10 to 15 digits, as accepted on profiles and tickets
+	{regexp.MustCompile(`(?:\+|\b)\d{10,15}\b`), Redacted},
+}
+
+// New returns a JSON logger writing to w at the given level ("debug", "info", "warn" or
+// "error"; anything else means info)
+func New(w io.Writer, level string) *slog.Logger {
+	h := slog.NewJSONHandler(w, &slog.HandlerOptions{
+		Level:       ParseLevel(level),
+		ReplaceAttr: redact,
+	})
+	return slog.New(contextHandler{h})
+}
+
+// Setup installs New(w, level) as the default logger, which also routes the standard log
+// package through it
+func Setup(w io.Writer, level string) *slog.Logger {
+	logger := New(w, level)
+	slog.SetDefault(logger)
+	return logger
+}
+
+// ParseLevel reads a level name, case-insensitively; unknown names mean info
+func ParseLevel(s string) slog.Level {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(strings.TrimSpace(s))); err != nil {
+		return slog.LevelInfo
+	}
+	return l
+}
+
+// Scrub removes tokens, secrets and phone numbers from free text
+func Scrub(s string) string {
+	for _, p := range sensitivePatterns {
+		s = p.re.ReplaceAllString(s, p.repl)
+	}
+	return s
+}
+
+func redact(_ []string, a slog.Attr) slog.Attr {
+	if a.Key == slog.TimeKey || a.Key == slog.LevelKey || a.Key == RequestIDKey {
+		return a
+	}
+	key := strings.ToLower(a.Key)
+	for _, k := range sensitiveKeys {
+		if strings.Contains(key, k) {
+			return slog.String(a.Key, Redacted)
+		}
+	}
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, Scrub(a.Value.String()))
+	case slog.KindAny:
+		if err, ok := a.Value.Any().(error); ok {
+			return slog.String(a.Key, Scrub(err.Error()))
+		}
+	}
+	return a
+}
+
+// RequestIDKey is the attribute holding the request ID
+const RequestIDKey = "request_id"
+
+type requestIDKey struct{}
+
+// WithRequestID returns a context whose log records carry the request ID
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID stored in ctx, if any
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// contextHandler adds the request ID from the record's context, so slog.InfoContext(ctx, ...)
+// anywhere below a handler is tied to its request
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		r.AddAttrs(slog.String(RequestIDKey, id))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,102 @@
+// Package metrics holds the app's Prometheus metrics and serves them on /metrics. Label
+// values come from bounded sets (route patterns, limiter prefixes, predefined locations) so
+// the number of series stays small.
+package metrics
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "travelsync"
+
+// Registry holds every metric below plus the Go runtime and process collectors
+var Registry = prometheus.NewRegistry()
+
+var (
+	// HTTPRequests counts finished requests by method, route pattern and status code
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route and status code.",
+	}, []string{"method", "route", "status"})
+
+	// HTTPRequestDuration is the time to serve a request by method and route pattern
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Time to serve HTTP requests by method and route.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	// RateLimitRejections counts requests refused by a rate limiter, by its prefix
+	RateLimitRejections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limit_rejections_total",
+		Help:      "Requests rejected by rate limiting, by limiter prefix.",
+	}, []string{"prefix"})
+
+	// RecommendationCandidates is the number of tickets a recommendation considered, both as
+	// returned by the candidate query ("fetched") and after compatibility filters ("scored")
+	RecommendationCandidates = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "recommendation_candidates",
+		Help:      "Candidate tickets per recommendation, fetched and left to score.",
+		Buckets:   []float64{0, 1, 2, 5, 10, 20, 50, 100, 200, 500, 1000},
+	}, []string{"stage"})
+
+	// RecommendationScoringDuration is the time spent scoring and ranking the candidates of
+	// one uncached recommendation
+	RecommendationScoringDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "recommendation_scoring_duration_seconds",
+		Help:      "Time to score and rank the candidates of a recommendation.",
+		Buckets:   []float64{.0001, .0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	})
+
+	// TicketsCreated counts new tickets by source and destination
+	TicketsCreated = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tickets_created_total",
+		Help:      "Tickets created, by source and destination.",
+	}, []string{"source", "destination"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPRequestDuration,
+		RateLimitRejections,
+		RecommendationCandidates,
+		RecommendationScoringDuration,
+		TicketsCreated,
+	)
+}
+
+// RegisterDB exports the connection pool stats of db (open, in use and idle connections,
+// waits for a connection and connections closed for being idle or too old)
+func RegisterDB(db *sql.DB) error {
+	return Registry.Register(collectors.NewDBStatsCollector(db, "postgres"))
+}
+
+// Handler serves the registry in the Prometheus text format. With a non-empty token,
+// scrapers must send "Authorization: Bearer <token>".
+func Handler(token string) gin.HandlerFunc {
+	h := promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+	want := []byte("Bearer " + token)
+	return func(c *gin.Context) {
+		if token != "" && subtle.ConstantTimeCompare([]byte(c.GetHeader("Authorization")), want) != 1 {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		h.ServeHTTP(c.Writer, c.Request)
+	}
+}
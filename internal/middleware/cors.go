@@ -64,8 +64,8 @@ func SetupCORS(appCfg *config.AppConfig) gin.HandlerFunc {
 			return false
 		},
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "Cookie"},
-		ExposeHeaders:    []string{"Content-Length", "Set-Cookie"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "Cookie", RequestIDHeader},
+		ExposeHeaders:    []string{"Content-Length", "Set-Cookie", RequestIDHeader},
 		AllowCredentials: true, // ✅ allow cookies
 		MaxAge:           12 * time.Hour,
 	})
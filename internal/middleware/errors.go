@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http"
+
+	"Travel_Sync/internal/apperr"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProblemContentType is the media type of RFC 7807 problem details
+const ProblemContentType = "application/problem+json"
+
+// problemTypePrefix makes error codes into problem type URIs, e.g. urn:travelsync:problem:ticket_not_found
+const problemTypePrefix = "urn:travelsync:problem:"
+
+// ErrorRenderer writes the response for handlers and middlewares that record an error with
+// c.Error instead of writing one. The last error decides the status and the body, an RFC 7807
+// problem that also carries the usual {"success": false, "error": message} envelope and the
+// error code; validation errors list each offending field under "errors". Internal errors are
+// shown to the client only as a generic message; RequestLogger logs their cause.
+func ErrorRenderer() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		err := c.Errors.Last().Err
+		e := apperr.From(err)
+		if e.Kind == apperr.KindCanceled {
+			// The client went away, nobody is listening
+			c.AbortWithStatus(e.Kind.Status())
+			return
+		}
+		status := e.Kind.Status()
+		body := gin.H{
+			"type":     problemTypePrefix + e.Code,
+			"title":    http.StatusText(status),
+			"status":   status,
+			"detail":   e.Message,
+			"instance": c.Request.URL.Path,
+			"success":  false,
+			"error":    e.Message,
+			"code":     e.Code,
+		}
+		if len(e.Fields) > 0 {
+			body["errors"] = e.Fields
+		}
+		for k, v := range e.Details {
+			body[k] = v
+		}
+		c.Abort()
+		c.Header("Content-Type", ProblemContentType)
+		c.JSON(status, body)
+	}
+}
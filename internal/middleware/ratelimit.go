@@ -6,6 +6,9 @@ import (
 	"sync"
 	"time"
 
+	"Travel_Sync/internal/apperr"
+	"Travel_Sync/internal/metrics"
+
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"golang.org/x/time/rate"
@@ -73,10 +76,7 @@ func RateLimitMiddleware(config RateLimitConfig) gin.HandlerFunc {
 			case int64:
 				key = config.Prefix + ":user:" + fmt.Sprintf("%d", v)
 			default:
-				c.JSON(http.StatusUnauthorized, gin.H{
-					"success": false,
-					"error":   "invalid user id type",
-				})
+				c.Error(apperr.Unauthorized("invalid_user_context", "invalid user id type"))
 				c.Abort()
 				return
 			}
@@ -111,12 +111,9 @@ func RateLimitMiddleware(config RateLimitConfig) gin.HandlerFunc {
 
 		// Deny if not allowed
 		if !limiter.Allow() {
+			metrics.RateLimitRejections.WithLabelValues(config.Prefix).Inc()
 			retryAfter := limiter.Reserve().Delay().Seconds()
-			c.JSON(http.StatusTooManyRequests, gin.H{
-				"success":     false,
-				"error":       "Rate limit exceeded. Please try again later.",
-				"retry_after": retryAfter,
-			})
+			c.Error(apperr.RateLimited("Rate limit exceeded. Please try again later.", retryAfter))
 			c.Abort()
 			return
 		}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"runtime/debug"
+	"strings"
+
+	"Travel_Sync/internal/apperr"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Recovery turns a panic in a handler into a 500 rendered by ErrorRenderer, and logs the
+// panic and stack through slog. Unlike gin.Recovery it never dumps request headers, which
+// carry the jwt_token cookie with the user's Google tokens.
+func Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+			if brokenConnection(r) {
+				// The client is gone, there is nobody to answer
+				c.Abort()
+				return
+			}
+			slog.ErrorContext(c.Request.Context(), "panic recovered",
+				"method", c.Request.Method,
+				"route", c.FullPath(),
+				"panic", fmt.Sprint(r),
+				"stack", string(debug.Stack()))
+			c.Error(apperr.ErrInternal.Wrap(fmt.Errorf("panic: %v", r)))
+			c.Abort()
+		}()
+		c.Next()
+	}
+}
+
+// brokenConnection reports panics from writing to a client that closed the connection
+func brokenConnection(r any) bool {
+	err, ok := r.(error)
+	if !ok {
+		return false
+	}
+	var opErr *net.OpError
+	var sysErr *os.SyscallError
+	if !errors.As(err, &opErr) || !errors.As(opErr, &sysErr) {
+		return false
+	}
+	msg := strings.ToLower(sysErr.Error())
+	return strings.Contains(msg, "broken pipe") || strings.Contains(msg, "connection reset by peer")
+}
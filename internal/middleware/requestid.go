@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"regexp"
+
+	"Travel_Sync/internal/logging"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader is the header clients and proxies use to correlate a request
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDContextKey holds the request ID in the gin context
+const requestIDContextKey = "request_id"
+
+// validRequestID keeps IDs from clients short and printable so they are safe to log and echo
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestID reuses the X-Request-ID sent by the client or load balancer, or generates one,
+// and puts it in the gin context, the request context (so logs below carry it) and the
+// response header
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(id) {
+			id = uuid.NewString()
+		}
+		c.Set(requestIDContextKey, id)
+		c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), id))
+		c.Header(RequestIDHeader, id)
+		c.Next()
+	}
+}
+
+// GetRequestID returns the ID of the request, as set by RequestID or else supplied with it
+func GetRequestID(c *gin.Context) string {
+	if id := c.GetString(requestIDContextKey); id != "" {
+		return id
+	}
+	return c.GetHeader(RequestIDHeader)
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// QueryTimeout puts a deadline on the request context, which services pass down to every
+// database call. The deadline is def unless perRoute has an entry for "METHOD /route/:param".
+// Handlers are not interrupted; their queries fail with context.DeadlineExceeded.
+func QueryTimeout(def time.Duration, perRoute map[string]time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		timeout, ok := perRoute[c.Request.Method+" "+c.FullPath()]
+		if !ok {
+			timeout = def
+		}
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+// Notification is an in-app message for a user, e.g. a waitlist promotion
+type Notification struct {
+	ID        int64      `gorm:"primaryKey;autoIncrement;not null" json:"id"`
+	UserID    int64      `gorm:"not null;index" json:"-"`
+	Kind      string     `gorm:"size:32;not null" json:"kind"`
+	Message   string     `gorm:"size:500;not null" json:"message"`
+	TicketID  *int64     `json:"ticket_id,omitempty"`
+	ReadAt    *time.Time `json:"read_at"`
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"Travel_Sync/internal/apperr"
+	"Travel_Sync/internal/notification/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationHandler struct {
+	Svc *service.NotificationService
+}
+
+func NewNotificationHandler(svc *service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{Svc: svc}
+}
+
+// List returns the caller's notifications; ?unread=true limits it to unread ones
+func (h *NotificationHandler) List(c *gin.Context) {
+	uid, ok := c.Get("user_id")
+	if !ok {
+		c.Error(apperr.ErrUnauthorized)
+		return
+	}
+	list, err := h.Svc.List(c.Request.Context(), toInt64(uid), c.Query("unread") == "true")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": list})
+}
+
+// MarkRead marks a single notification as read
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	uid, ok := c.Get("user_id")
+	if !ok {
+		c.Error(apperr.ErrUnauthorized)
+		return
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.Error(apperr.ErrInvalidID)
+		return
+	}
+	if err := h.Svc.MarkRead(c.Request.Context(), toInt64(uid), id); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": "notification marked as read"})
+}
+
+// MarkAllRead marks all of the caller's notifications as read
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	uid, ok := c.Get("user_id")
+	if !ok {
+		c.Error(apperr.ErrUnauthorized)
+		return
+	}
+	if err := h.Svc.MarkAllRead(c.Request.Context(), toInt64(uid)); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": "notifications marked as read"})
+}
+
+func toInt64(v interface{}) int64 {
+	if id, ok := v.(int64); ok {
+		return id
+	}
+	if f, ok := v.(float64); ok {
+		return int64(f)
+	}
+	return 0
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"Travel_Sync/internal/notification/entity"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type NotificationRepo struct {
+	DB *gorm.DB
+}
+
+func NewNotificationRepo(db *gorm.DB) *NotificationRepo {
+	return &NotificationRepo{DB: db}
+}
+
+func (r *NotificationRepo) Create(ctx context.Context, n *entity.Notification) error {
+	return r.DB.WithContext(ctx).Create(n).Error
+}
+
+// ListForUser returns the user's notifications, newest first
+func (r *NotificationRepo) ListForUser(ctx context.Context, userID int64, unreadOnly bool, limit int) ([]entity.Notification, error) {
+	var list []entity.Notification
+	q := r.DB.WithContext(ctx).Where("user_id = ?", userID)
+	if unreadOnly {
+		q = q.Where("read_at IS NULL")
+	}
+	err := q.Order("created_at DESC").Limit(limit).Find(&list).Error
+	return list, err
+}
+
+// ListAllForUser returns every notification of the user, oldest first and without paging
+func (r *NotificationRepo) ListAllForUser(ctx context.Context, userID int64) ([]entity.Notification, error) {
+	var list []entity.Notification
+	err := r.DB.WithContext(ctx).Where("user_id = ?", userID).Order("created_at, id").Find(&list).Error
+	return list, err
+}
+
+// MarkRead marks one of the user's notifications as read, returning gorm.ErrRecordNotFound
+// if the user has no such notification
+func (r *NotificationRepo) MarkRead(ctx context.Context, userID, id int64, at time.Time) error {
+	res := r.DB.WithContext(ctx).Model(&entity.Notification{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Update("read_at", at)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// MarkAllRead marks every unread notification of the user as read
+func (r *NotificationRepo) MarkAllRead(ctx context.Context, userID int64, at time.Time) error {
+	return r.DB.WithContext(ctx).Model(&entity.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", at).Error
+}
+
+// DeleteByUserIDs removes all notifications of the given users
+func (r *NotificationRepo) DeleteByUserIDs(ctx context.Context, userIDs []int64) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	return r.DB.WithContext(ctx).Where("user_id IN ?", userIDs).Delete(&entity.Notification{}).Error
+}
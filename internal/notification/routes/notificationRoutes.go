@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"Travel_Sync/internal/middleware"
+	"Travel_Sync/internal/notification/handler"
+	"Travel_Sync/internal/security/config"
+	"Travel_Sync/internal/security/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterNotificationRoutes(router *gin.Engine, notificationHandler *handler.NotificationHandler, jwtService *service.JWTService) {
+	api := router.Group("/api")
+	{
+		notifications := api.Group("/notifications")
+		notifications.Use(config.JWTMiddleware(jwtService))
+		notifications.Use(middleware.GeneralRateLimiter())
+		{
+			notifications.GET("", notificationHandler.List)
+			notifications.POST("/read", notificationHandler.MarkAllRead)
+			notifications.POST("/:id/read", notificationHandler.MarkRead)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"Travel_Sync/internal/apperr"
+	"Travel_Sync/internal/notification/entity"
+	"Travel_Sync/internal/notification/repository"
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Notification kinds
+const (
+	KindWaitlistPromoted  = "waitlist_promoted"
+	KindWaitlistCancelled = "waitlist_cancelled"
+	KindWaitlistExpired   = "waitlist_expired"
+)
+
+var ErrNotificationNotFound = apperr.NotFound("notification_not_found", "notification not found")
+
+// maxListed caps how many notifications are returned at once
+const maxListed = 100
+
+type NotificationService struct {
+	Repo *repository.NotificationRepo
+}
+
+func NewNotificationService(repo *repository.NotificationRepo) *NotificationService {
+	return &NotificationService{Repo: repo}
+}
+
+// Notify stores a notification for the user. Like auditing, failures are only logged so
+// that they never undo the change being notified about, and the notification is stored
+// even if ctx is cancelled meanwhile. A nil service is a no-op.
+func (s *NotificationService) Notify(ctx context.Context, userID int64, kind, message string, ticketID *int64) {
+	if s == nil || s.Repo == nil {
+		return
+	}
+	ctx = context.WithoutCancel(ctx)
+	n := &entity.Notification{UserID: userID, Kind: kind, Message: message, TicketID: ticketID}
+	if err := s.Repo.Create(ctx, n); err != nil {
+		slog.ErrorContext(ctx, "notification: failed to notify user", "user_id", userID, "kind", kind, "error", err)
+	}
+}
+
+func (s *NotificationService) List(ctx context.Context, userID int64, unreadOnly bool) ([]entity.Notification, error) {
+	return s.Repo.ListForUser(ctx, userID, unreadOnly, maxListed)
+}
+
+func (s *NotificationService) MarkRead(ctx context.Context, userID, id int64) error {
+	return apperr.NotFoundAs(s.Repo.MarkRead(ctx, userID, id, time.Now().UTC()), ErrNotificationNotFound)
+}
+
+func (s *NotificationService) MarkAllRead(ctx context.Context, userID int64) error {
+	return s.Repo.MarkAllRead(ctx, userID, time.Now().UTC())
+}
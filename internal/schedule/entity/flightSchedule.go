@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+// FlightSchedule is one scheduled operation of a flight at Bengaluru airport.
+// ArrivalAt is set for flights landing here, DepartureAt for flights leaving from here.
+type FlightSchedule struct {
+	ID           int64      `gorm:"primaryKey;autoIncrement;not null" json:"id"`
+	FlightNumber string     `gorm:"size:16;not null;uniqueIndex:idx_flight_schedules_number_date" json:"flight_number"`
+	ServiceDate  string     `gorm:"type:varchar(10);not null;uniqueIndex:idx_flight_schedules_number_date" json:"service_date"` // local date, 2006-01-02
+	Terminal     string     `gorm:"size:255;not null" json:"terminal"`
+	ArrivalAt    *time.Time `gorm:"type:timestamptz" json:"arrival_at,omitempty"`
+	DepartureAt  *time.Time `gorm:"type:timestamptz" json:"departure_at,omitempty"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
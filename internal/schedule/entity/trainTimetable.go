@@ -0,0 +1,13 @@
+package entity
+
+import "time"
+
+// TrainTimetable is a daily scheduled stop of a train at one of the Bengaluru stations
+type TrainTimetable struct {
+	ID            int64     `gorm:"primaryKey;autoIncrement;not null" json:"id"`
+	TrainNumber   string    `gorm:"size:16;not null;uniqueIndex:idx_train_timetables_number_station" json:"train_number"`
+	Station       string    `gorm:"size:255;not null;uniqueIndex:idx_train_timetables_number_station" json:"station"`
+	ScheduledTime string    `gorm:"type:varchar(5);not null" json:"scheduled_time"` // local campus time, 15:04
+	CreatedAt     time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"Travel_Sync/internal/apperr"
+	"Travel_Sync/internal/schedule/models"
+	"Travel_Sync/internal/schedule/service"
+	"errors"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportBytes caps the size of an uploaded schedule file
+const maxImportBytes = 10 << 20
+
+var (
+	errFlightNotFound = apperr.NotFound("flight_not_found", "flight not found")
+	errTrainNotFound  = apperr.NotFound("train_not_found", "train not found")
+	errInvalidFile    = apperr.Validation("invalid_file", "invalid file")
+	errImportFailed   = apperr.New(apperr.KindInternal, "import_failed", "import failed")
+)
+
+type ScheduleHandler struct {
+	Flights *service.FlightScheduleService
+	Trains  *service.TrainTimetableService
+}
+
+func NewScheduleHandler(flights *service.FlightScheduleService, trains *service.TrainTimetableService) *ScheduleHandler {
+	return &ScheduleHandler{Flights: flights, Trains: trains}
+}
+
+// GetFlight looks up a flight by number, optionally on a given local date (?date=2006-01-02)
+func (h *ScheduleHandler) GetFlight(c *gin.Context) {
+	schedules, err := h.Flights.Lookup(c.Request.Context(), c.Param("number"), c.Query("date"))
+	if err != nil {
+		c.Error(apperr.NotFoundAs(err, errFlightNotFound))
+		return
+	}
+	if len(schedules) == 0 {
+		c.Error(errFlightNotFound)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": schedules})
+}
+
+// ImportFlights loads a CSV or JSON schedule file, either as a multipart "file" field or as the raw body
+func (h *ScheduleHandler) ImportFlights(c *gin.Context) {
+	body, format, err := readUpload(c)
+	if err != nil {
+		c.Error(errInvalidFile.WithMessage(err.Error()).Wrap(err))
+		return
+	}
+	defer body.Close()
+
+	var rows []models.FlightScheduleRow
+	if format == "json" {
+		rows, err = service.ParseFlightJSON(body)
+	} else {
+		rows, err = service.ParseFlightCSV(body)
+	}
+	if err != nil {
+		c.Error(errInvalidFile.WithMessage("invalid schedule file: " + err.Error()))
+		return
+	}
+
+	summary, err := h.Flights.Import(c.Request.Context(), rows)
+	if err != nil {
+		c.Error(importFailed(err, summary))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": summary})
+}
+
+// GetTrain returns the Bengaluru stops of a train
+func (h *ScheduleHandler) GetTrain(c *gin.Context) {
+	stops, err := h.Trains.Lookup(c.Request.Context(), c.Param("number"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if len(stops) == 0 {
+		c.Error(errTrainNotFound)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": stops})
+}
+
+// ImportTrains loads a CSV or JSON timetable file, either as a multipart "file" field or as the raw body
+func (h *ScheduleHandler) ImportTrains(c *gin.Context) {
+	body, format, err := readUpload(c)
+	if err != nil {
+		c.Error(errInvalidFile.WithMessage(err.Error()).Wrap(err))
+		return
+	}
+	defer body.Close()
+
+	var rows []models.TrainTimetableRow
+	if format == "json" {
+		rows, err = service.ParseTrainJSON(body)
+	} else {
+		rows, err = service.ParseTrainCSV(body)
+	}
+	if err != nil {
+		c.Error(errInvalidFile.WithMessage("invalid timetable file: " + err.Error()))
+		return
+	}
+
+	summary, err := h.Trains.Import(c.Request.Context(), rows)
+	if err != nil {
+		c.Error(importFailed(err, summary))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": summary})
+}
+
+// importFailed reports an import that stopped part way, with the summary of the rows handled
+// before it stopped under "data". A deadline or cancellation keeps its own kind.
+func importFailed(err error, summary *models.ImportSummary) error {
+	e := *apperr.From(err)
+	if e.Kind == apperr.KindInternal {
+		e = *errImportFailed.Wrap(err)
+	}
+	e.Details = map[string]any{"data": summary}
+	return &e
+}
+
+// readUpload returns the uploaded file and whether it is "csv" or "json". The format comes
+// from ?format=, else the file extension, else the Content-Type.
+func readUpload(c *gin.Context) (io.ReadCloser, string, error) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBytes)
+	format := strings.ToLower(c.Query("format"))
+
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		fh, err := c.FormFile("file")
+		if err != nil {
+			return nil, "", errors.New("file is required")
+		}
+		if format == "" {
+			format = strings.TrimPrefix(strings.ToLower(filepath.Ext(fh.Filename)), ".")
+		}
+		f, err := fh.Open()
+		if err != nil {
+			return nil, "", err
+		}
+		return f, normalizeFormat(format), nil
+	}
+
+	if format == "" && strings.Contains(c.ContentType(), "json") {
+		format = "json"
+	}
+	return c.Request.Body, normalizeFormat(format), nil
+}
+
+func normalizeFormat(f string) string {
+	if f == "json" {
+		return "json"
+	}
+	return "csv"
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"Travel_Sync/internal/apperr"
+	"Travel_Sync/internal/schedule/entity"
+	tmodels "Travel_Sync/internal/travel/models"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// FlightDepartureLead is how long before a departing flight riders leave campus
+	FlightDepartureLead = 3 * time.Hour
+	// FlightArrivalBuffer is how long after landing riders are expected at the pickup point
+	FlightArrivalBuffer = 30 * time.Minute
+)
+
+var nonAlnum = regexp.MustCompile(`[^A-Z0-9]`)
+
+// FlightScheduleRow is one entry of an imported schedule file (CSV header or JSON keys)
+type FlightScheduleRow struct {
+	FlightNumber string `json:"flight_number"`
+	Terminal     string `json:"terminal"`
+	ArrivalAt    string `json:"arrival_at"`   // RFC3339 or local campus time, optional
+	DepartureAt  string `json:"departure_at"` // RFC3339 or local campus time, optional
+}
+
+// RowError reports a row that could not be imported; Row is 1-based, excluding any header
+type RowError struct {
+	Row   int    `json:"row"`
+	Error string `json:"error"`
+}
+
+type ImportSummary struct {
+	Created         int        `json:"created"`
+	Updated         int        `json:"updated"`
+	Unchanged       int        `json:"unchanged"`
+	TicketsAdjusted int        `json:"tickets_adjusted,omitempty"`
+	Errors          []RowError `json:"errors"`
+}
+
+// NormalizeFlightNumber upper-cases and strips spaces and dashes, e.g. "6e-123" → "6E123"
+func NormalizeFlightNumber(n string) string {
+	return nonAlnum.ReplaceAllString(strings.ToUpper(strings.TrimSpace(n)), "")
+}
+
+// NormalizeTerminal maps "T1", "1", "Terminal 1" or the full name onto a known airport terminal
+func NormalizeTerminal(t string) (string, error) {
+	t = strings.TrimSpace(t)
+	if tmodels.IsAirportTerminal(t) {
+		return t, nil
+	}
+	short := strings.ToUpper(strings.ReplaceAll(t, " ", ""))
+	short = strings.TrimPrefix(short, "TERMINAL")
+	short = strings.TrimPrefix(short, "T")
+	short = strings.TrimPrefix(short, "-")
+	switch short {
+	case "1":
+		return "Kempegowda International Airport Terminal-1", nil
+	case "2":
+		return "Kempegowda International Airport Terminal-2", nil
+	}
+	return "", errors.New("unknown terminal " + t)
+}
+
+// FlightTicketLeg derives the airport end of a ticket and its departure time from a schedule.
+// Outbound riders leave campus FlightDepartureLead before take-off; return riders leave the
+// airport FlightArrivalBuffer after landing.
+func FlightTicketLeg(s *entity.FlightSchedule, direction string) (string, time.Time, error) {
+	if direction == tmodels.DirectionReturn {
+		if s.ArrivalAt == nil {
+			return "", time.Time{}, apperr.Validation("flight_not_arriving", "flight "+s.FlightNumber+" does not arrive at Bengaluru")
+		}
+		return s.Terminal, s.ArrivalAt.UTC().Add(FlightArrivalBuffer), nil
+	}
+	if s.DepartureAt == nil {
+		return "", time.Time{}, apperr.Validation("flight_not_departing", "flight "+s.FlightNumber+" does not depart from Bengaluru")
+	}
+	return s.Terminal, s.DepartureAt.UTC().Add(-FlightDepartureLead), nil
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"Travel_Sync/internal/apperr"
+	"Travel_Sync/internal/schedule/entity"
+	"Travel_Sync/internal/timezone"
+	tmodels "Travel_Sync/internal/travel/models"
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	// TrainDepartureLead is how long before the scheduled time riders leave campus
+	TrainDepartureLead = time.Hour
+	// TrainArrivalBuffer is how long after the scheduled arrival riders are expected at the pickup point
+	TrainArrivalBuffer = 15 * time.Minute
+)
+
+// StationCodes maps Indian Railways station codes onto the station names used in tickets
+var StationCodes = map[string]string{
+	"SBC":  "KSR SBC Bengaluru Junction Railway Station",
+	"SMVB": "SMVT Bengaluru Railway station",
+	"KJM":  "Krishnarajapuram Railway Station",
+	"YPR":  "Yesvantpur Junction Railway station",
+	"BNC":  "Banglore Cantonment Railway Station",
+	"BNCE": "Bengaluru East Railway Station",
+}
+
+// TrainTimetableRow is one entry of an imported timetable file (CSV header or JSON keys)
+type TrainTimetableRow struct {
+	TrainNumber   string `json:"train_number"`
+	Station       string `json:"station"`        // station code (SBC) or full name
+	ScheduledTime string `json:"scheduled_time"` // local campus time, 15:04
+}
+
+// NormalizeTrainNumber strips everything but digits and letters, e.g. "12627 " → "12627"
+func NormalizeTrainNumber(n string) string {
+	return nonAlnum.ReplaceAllString(strings.ToUpper(strings.TrimSpace(n)), "")
+}
+
+// NormalizeStation maps a station code or name onto a known railway station
+func NormalizeStation(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if tmodels.IsRailwayStation(s) {
+		return s, nil
+	}
+	if name, ok := StationCodes[strings.ToUpper(s)]; ok {
+		return name, nil
+	}
+	return "", errors.New("unknown station " + s)
+}
+
+// TrainTicketLeg derives the departure time of a ticket for a train stop on a local date (2006-01-02).
+// Outbound riders leave campus TrainDepartureLead before the train; return riders leave the
+// station TrainArrivalBuffer after it arrives.
+func TrainTicketLeg(stop *entity.TrainTimetable, date, direction string) (time.Time, error) {
+	at, err := time.ParseInLocation("2006-01-02 15:04", date+" "+stop.ScheduledTime, timezone.Default())
+	if err != nil {
+		return time.Time{}, apperr.Validation("invalid_train_date", "train_date must be in 2006-01-02 format")
+	}
+	if direction == tmodels.DirectionReturn {
+		return at.Add(TrainArrivalBuffer).UTC(), nil
+	}
+	return at.Add(-TrainDepartureLead).UTC(), nil
+}
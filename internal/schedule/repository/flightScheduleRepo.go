@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"Travel_Sync/internal/schedule/entity"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type FlightScheduleRepo struct {
+	DB *gorm.DB
+}
+
+func NewFlightScheduleRepo(db *gorm.DB) *FlightScheduleRepo {
+	return &FlightScheduleRepo{DB: db}
+}
+
+func (r *FlightScheduleRepo) Create(ctx context.Context, s *entity.FlightSchedule) (*entity.FlightSchedule, error) {
+	if err := r.DB.WithContext(ctx).Create(s).Error; err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (r *FlightScheduleRepo) Update(ctx context.Context, s *entity.FlightSchedule) (*entity.FlightSchedule, error) {
+	if err := r.DB.WithContext(ctx).Save(s).Error; err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// GetByNumberAndDate finds a flight by normalized number and local service date (2006-01-02)
+func (r *FlightScheduleRepo) GetByNumberAndDate(ctx context.Context, flightNumber, serviceDate string) (*entity.FlightSchedule, error) {
+	var s entity.FlightSchedule
+	if err := r.DB.WithContext(ctx).Where("flight_number = ? AND service_date = ?", flightNumber, serviceDate).First(&s).Error; err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// GetByNumber returns all scheduled operations of a flight, earliest date first
+func (r *FlightScheduleRepo) GetByNumber(ctx context.Context, flightNumber string) ([]entity.FlightSchedule, error) {
+	var out []entity.FlightSchedule
+	err := r.DB.WithContext(ctx).Where("flight_number = ?", flightNumber).Order("service_date").Find(&out).Error
+	return out, err
+}
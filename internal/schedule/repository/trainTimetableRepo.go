@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"Travel_Sync/internal/schedule/entity"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type TrainTimetableRepo struct {
+	DB *gorm.DB
+}
+
+func NewTrainTimetableRepo(db *gorm.DB) *TrainTimetableRepo {
+	return &TrainTimetableRepo{DB: db}
+}
+
+func (r *TrainTimetableRepo) Create(ctx context.Context, t *entity.TrainTimetable) (*entity.TrainTimetable, error) {
+	if err := r.DB.WithContext(ctx).Create(t).Error; err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (r *TrainTimetableRepo) Update(ctx context.Context, t *entity.TrainTimetable) (*entity.TrainTimetable, error) {
+	if err := r.DB.WithContext(ctx).Save(t).Error; err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// GetByNumberAndStation finds a single stop of a train
+func (r *TrainTimetableRepo) GetByNumberAndStation(ctx context.Context, trainNumber, station string) (*entity.TrainTimetable, error) {
+	var t entity.TrainTimetable
+	if err := r.DB.WithContext(ctx).Where("train_number = ? AND station = ?", trainNumber, station).First(&t).Error; err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// GetByNumber returns every Bengaluru stop of a train, in scheduled order
+func (r *TrainTimetableRepo) GetByNumber(ctx context.Context, trainNumber string) ([]entity.TrainTimetable, error) {
+	var out []entity.TrainTimetable
+	err := r.DB.WithContext(ctx).Where("train_number = ?", trainNumber).Order("scheduled_time").Find(&out).Error
+	return out, err
+}
@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"Travel_Sync/internal/middleware"
+	"Travel_Sync/internal/schedule/handler"
+	"Travel_Sync/internal/security/config"
+	"Travel_Sync/internal/security/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterScheduleRoutes(router *gin.Engine, scheduleHandler *handler.ScheduleHandler, jwtService *service.JWTService, adminEmails []string) {
+	api := router.Group("/api")
+	api.Use(config.JWTMiddleware(jwtService))
+	api.Use(middleware.GeneralRateLimiter())
+	{
+		api.GET("/flights/:number", scheduleHandler.GetFlight)
+		api.GET("/trains/:number", scheduleHandler.GetTrain)
+
+		admin := api.Group("/admin")
+		admin.Use(config.AdminMiddleware(adminEmails))
+		{
+			admin.POST("/flights/import", scheduleHandler.ImportFlights)
+			admin.POST("/trains/import", scheduleHandler.ImportTrains)
+		}
+	}
+}
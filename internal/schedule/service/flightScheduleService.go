@@ -0,0 +1,210 @@
+package service
+
+import (
+	amodels "Travel_Sync/internal/audit/models"
+	aservice "Travel_Sync/internal/audit/service"
+	"Travel_Sync/internal/schedule/entity"
+	"Travel_Sync/internal/schedule/models"
+	"Travel_Sync/internal/schedule/repository"
+	"Travel_Sync/internal/timezone"
+	tmodels "Travel_Sync/internal/travel/models"
+	trepo "Travel_Sync/internal/travel/repository"
+	tservice "Travel_Sync/internal/travel/service"
+	wservice "Travel_Sync/internal/waitlist/service"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type FlightScheduleService struct {
+	Repo       *repository.FlightScheduleRepo
+	TicketRepo trepo.TravelTicketRepository
+	Audit      *aservice.AuditService
+	Waitlist   *wservice.WaitlistService
+
+	Recommendations *tservice.RecommendationCache
+}
+
+func NewFlightScheduleService(repo *repository.FlightScheduleRepo, ticketRepo trepo.TravelTicketRepository, audit *aservice.AuditService, waitlist *wservice.WaitlistService, recommendations *tservice.RecommendationCache) *FlightScheduleService {
+	return &FlightScheduleService{Repo: repo, TicketRepo: ticketRepo, Audit: audit, Waitlist: waitlist, Recommendations: recommendations}
+}
+
+// Lookup returns the operations of a flight, optionally restricted to one local date (2006-01-02)
+func (s *FlightScheduleService) Lookup(ctx context.Context, flightNumber, date string) ([]entity.FlightSchedule, error) {
+	number := models.NormalizeFlightNumber(flightNumber)
+	if date == "" {
+		return s.Repo.GetByNumber(ctx, number)
+	}
+	sched, err := s.Repo.GetByNumberAndDate(ctx, number, date)
+	if err != nil {
+		return nil, err
+	}
+	return []entity.FlightSchedule{*sched}, nil
+}
+
+// ParseFlightCSV reads schedule rows from CSV with a header naming the FlightScheduleRow columns
+func ParseFlightCSV(r io.Reader) ([]models.FlightScheduleRow, error) {
+	records, err := readCSV(r, "flight_number")
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]models.FlightScheduleRow, 0, len(records))
+	for _, rec := range records {
+		rows = append(rows, models.FlightScheduleRow{
+			FlightNumber: rec["flight_number"],
+			Terminal:     rec["terminal"],
+			ArrivalAt:    rec["arrival_at"],
+			DepartureAt:  rec["departure_at"],
+		})
+	}
+	return rows, nil
+}
+
+// ParseFlightJSON reads schedule rows from a JSON array of FlightScheduleRow objects
+func ParseFlightJSON(r io.Reader) ([]models.FlightScheduleRow, error) {
+	var rows []models.FlightScheduleRow
+	if err := json.NewDecoder(r).Decode(&rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// Import upserts schedule rows keyed by flight number and local service date. When an
+// existing flight changes, every ticket linked to it is re-derived from the new schedule.
+func (s *FlightScheduleService) Import(ctx context.Context, rows []models.FlightScheduleRow) (*models.ImportSummary, error) {
+	summary := &models.ImportSummary{Errors: []models.RowError{}}
+	for i, row := range rows {
+		parsed, err := parseRow(row)
+		if err != nil {
+			summary.Errors = append(summary.Errors, models.RowError{Row: i + 1, Error: err.Error()})
+			continue
+		}
+
+		existing, err := s.Repo.GetByNumberAndDate(ctx, parsed.FlightNumber, parsed.ServiceDate)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			if _, err := s.Repo.Create(ctx, parsed); err != nil {
+				return summary, err
+			}
+			summary.Created++
+			continue
+		}
+		if err != nil {
+			return summary, err
+		}
+
+		if sameSchedule(existing, parsed) {
+			summary.Unchanged++
+			continue
+		}
+		existing.Terminal = parsed.Terminal
+		existing.ArrivalAt = parsed.ArrivalAt
+		existing.DepartureAt = parsed.DepartureAt
+		if _, err := s.Repo.Update(ctx, existing); err != nil {
+			return summary, err
+		}
+		summary.Updated++
+
+		adjusted, err := s.adjustLinkedTickets(ctx, existing)
+		if err != nil {
+			return summary, err
+		}
+		summary.TicketsAdjusted += adjusted
+	}
+	return summary, nil
+}
+
+// adjustLinkedTickets moves every ticket linked to the flight onto its new terminal and time
+func (s *FlightScheduleService) adjustLinkedTickets(ctx context.Context, sched *entity.FlightSchedule) (int, error) {
+	tickets, err := s.TicketRepo.GetByFlightScheduleID(ctx, sched.ID)
+	if err != nil {
+		return 0, err
+	}
+	adjusted := 0
+	for i := range tickets {
+		t := &tickets[i]
+		before := *t
+		direction := tmodels.TripDirection(t.Source, t.Destination)
+		terminal, departureAt, err := models.FlightTicketLeg(sched, direction)
+		if err != nil {
+			slog.WarnContext(ctx, "flights: cannot adjust ticket", "ticket_id", t.ID, "flight_number", sched.FlightNumber, "error", err)
+			continue
+		}
+		if direction == tmodels.DirectionReturn {
+			t.Source = terminal
+		} else {
+			t.Destination = terminal
+		}
+		t.DepartureAt = departureAt
+		if _, err := s.TicketRepo.Update(ctx, t); err != nil {
+			return adjusted, err
+		}
+		s.Audit.Record(ctx, amodels.AuditEntry{
+			ActorID:    0, // system
+			OwnerID:    t.UserID,
+			Action:     amodels.ActionUpdate,
+			EntityType: amodels.EntityTicket,
+			EntityID:   t.ID,
+			Before:     &before,
+			After:      t,
+		})
+		if !t.DepartureAt.Equal(before.DepartureAt) {
+			s.Waitlist.TicketRescheduled(ctx, t)
+		}
+		s.Recommendations.Invalidate(&before, t)
+		adjusted++
+	}
+	return adjusted, nil
+}
+
+func parseRow(row models.FlightScheduleRow) (*entity.FlightSchedule, error) {
+	number := models.NormalizeFlightNumber(row.FlightNumber)
+	if number == "" {
+		return nil, errors.New("flight_number is required")
+	}
+	terminal, err := models.NormalizeTerminal(row.Terminal)
+	if err != nil {
+		return nil, err
+	}
+	out := &entity.FlightSchedule{FlightNumber: number, Terminal: terminal}
+	if row.ArrivalAt != "" {
+		t, err := timezone.ParseDateTime(row.ArrivalAt, timezone.Default())
+		if err != nil {
+			return nil, fmt.Errorf("arrival_at: %w", err)
+		}
+		out.ArrivalAt = &t
+	}
+	if row.DepartureAt != "" {
+		t, err := timezone.ParseDateTime(row.DepartureAt, timezone.Default())
+		if err != nil {
+			return nil, fmt.Errorf("departure_at: %w", err)
+		}
+		out.DepartureAt = &t
+	}
+	// The service date is the campus-local date of the first event at Bengaluru
+	switch {
+	case out.ArrivalAt != nil:
+		out.ServiceDate = out.ArrivalAt.In(timezone.Default()).Format("2006-01-02")
+	case out.DepartureAt != nil:
+		out.ServiceDate = out.DepartureAt.In(timezone.Default()).Format("2006-01-02")
+	default:
+		return nil, errors.New("arrival_at or departure_at is required")
+	}
+	return out, nil
+}
+
+func sameSchedule(a, b *entity.FlightSchedule) bool {
+	return a.Terminal == b.Terminal && sameTime(a.ArrivalAt, b.ArrivalAt) && sameTime(a.DepartureAt, b.DepartureAt)
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
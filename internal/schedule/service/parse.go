@@ -0,0 +1,45 @@
+package service
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+)
+
+// readCSV reads a CSV file with a header row into one map per record, keyed by
+// lower-cased column name. The required column must be present in the header.
+func readCSV(r io.Reader, required string) ([]map[string]string, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	cr.FieldsPerRecord = -1
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errors.New("schedule file is empty")
+	}
+	header := make([]string, len(records[0]))
+	found := false
+	for i, h := range records[0] {
+		header[i] = strings.ToLower(strings.TrimSpace(h))
+		if header[i] == required {
+			found = true
+		}
+	}
+	if !found {
+		return nil, errors.New("missing " + required + " column")
+	}
+	out := make([]map[string]string, 0, len(records)-1)
+	for _, rec := range records[1:] {
+		row := make(map[string]string, len(header))
+		for i, name := range header {
+			if i < len(rec) {
+				row[name] = strings.TrimSpace(rec[i])
+			}
+		}
+		out = append(out, row)
+	}
+	return out, nil
+}
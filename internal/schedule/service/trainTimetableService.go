@@ -0,0 +1,98 @@
+package service
+
+import (
+	"Travel_Sync/internal/schedule/entity"
+	"Travel_Sync/internal/schedule/models"
+	"Travel_Sync/internal/schedule/repository"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type TrainTimetableService struct {
+	Repo *repository.TrainTimetableRepo
+}
+
+func NewTrainTimetableService(repo *repository.TrainTimetableRepo) *TrainTimetableService {
+	return &TrainTimetableService{Repo: repo}
+}
+
+// Lookup returns the Bengaluru stops of a train
+func (s *TrainTimetableService) Lookup(ctx context.Context, trainNumber string) ([]entity.TrainTimetable, error) {
+	return s.Repo.GetByNumber(ctx, models.NormalizeTrainNumber(trainNumber))
+}
+
+// ParseTrainCSV reads timetable rows from CSV with a header naming the TrainTimetableRow columns
+func ParseTrainCSV(r io.Reader) ([]models.TrainTimetableRow, error) {
+	records, err := readCSV(r, "train_number")
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]models.TrainTimetableRow, 0, len(records))
+	for _, rec := range records {
+		rows = append(rows, models.TrainTimetableRow{
+			TrainNumber:   rec["train_number"],
+			Station:       rec["station"],
+			ScheduledTime: rec["scheduled_time"],
+		})
+	}
+	return rows, nil
+}
+
+// ParseTrainJSON reads timetable rows from a JSON array of TrainTimetableRow objects
+func ParseTrainJSON(r io.Reader) ([]models.TrainTimetableRow, error) {
+	var rows []models.TrainTimetableRow
+	if err := json.NewDecoder(r).Decode(&rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// Import upserts timetable rows keyed by train number and station
+func (s *TrainTimetableService) Import(ctx context.Context, rows []models.TrainTimetableRow) (*models.ImportSummary, error) {
+	summary := &models.ImportSummary{Errors: []models.RowError{}}
+	for i, row := range rows {
+		number := models.NormalizeTrainNumber(row.TrainNumber)
+		if number == "" {
+			summary.Errors = append(summary.Errors, models.RowError{Row: i + 1, Error: "train_number is required"})
+			continue
+		}
+		station, err := models.NormalizeStation(row.Station)
+		if err != nil {
+			summary.Errors = append(summary.Errors, models.RowError{Row: i + 1, Error: err.Error()})
+			continue
+		}
+		scheduled, err := time.Parse("15:04", row.ScheduledTime)
+		if err != nil {
+			summary.Errors = append(summary.Errors, models.RowError{Row: i + 1, Error: "scheduled_time must be HH:MM"})
+			continue
+		}
+		hhmm := scheduled.Format("15:04")
+
+		existing, err := s.Repo.GetByNumberAndStation(ctx, number, station)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			if _, err := s.Repo.Create(ctx, &entity.TrainTimetable{TrainNumber: number, Station: station, ScheduledTime: hhmm}); err != nil {
+				return summary, err
+			}
+			summary.Created++
+			continue
+		}
+		if err != nil {
+			return summary, err
+		}
+		if existing.ScheduledTime == hhmm {
+			summary.Unchanged++
+			continue
+		}
+		existing.ScheduledTime = hhmm
+		if _, err := s.Repo.Update(ctx, existing); err != nil {
+			return summary, err
+		}
+		summary.Updated++
+	}
+	return summary, nil
+}
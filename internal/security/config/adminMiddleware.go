@@ -0,0 +1,28 @@
+package config
+
+import (
+	"strings"
+
+	"Travel_Sync/internal/apperr"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminMiddleware only lets through users whose email is in adminEmails.
+// Must be registered after JWTMiddleware so that user_email is set.
+func AdminMiddleware(adminEmails []string) gin.HandlerFunc {
+	admins := make(map[string]struct{}, len(adminEmails))
+	for _, e := range adminEmails {
+		admins[strings.ToLower(e)] = struct{}{}
+	}
+	return func(c *gin.Context) {
+		email, _ := c.Get("user_email")
+		emailStr, _ := email.(string)
+		if _, ok := admins[strings.ToLower(emailStr)]; !ok {
+			c.Error(apperr.ErrForbidden)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"Travel_Sync/internal/apperr"
 	"Travel_Sync/internal/security/service"
 	"net/http"
 
@@ -19,7 +20,7 @@ func JWTMiddleware(jwtService *service.JWTService) gin.HandlerFunc {
 		// Get JWT token from cookie
 		tokenString, err := c.Cookie("jwt_token")
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "JWT token not found in cookies"})
+			c.Error(apperr.Unauthorized("missing_token", "JWT token not found in cookies"))
 			c.Abort()
 			return
 		}
@@ -27,7 +28,7 @@ func JWTMiddleware(jwtService *service.JWTService) gin.HandlerFunc {
 		// Validate JWT token
 		claims, err := jwtService.ValidateJWT(tokenString)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid JWT token"})
+			c.Error(apperr.Unauthorized("invalid_token", "Invalid JWT token"))
 			c.Abort()
 			return
 		}
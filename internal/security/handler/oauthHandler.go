@@ -1,9 +1,11 @@
 package handler
 
 import (
+	"Travel_Sync/internal/apperr"
 	"Travel_Sync/internal/security/service"
 	"encoding/hex"
 	"fmt"
+	"log/slog"
 	"math/rand"
 	"net/http"
 	"os"
@@ -37,14 +39,14 @@ func (h *OAuthHandler) GoogleCallback(c *gin.Context) {
 	state := c.Query("state")
 	cookieState, _ := c.Cookie("oauth_state")
 	if state == "" || cookieState == "" || state != cookieState {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid oauth state"})
+		c.Error(apperr.Validation("invalid_oauth_state", "invalid oauth state"))
 		return
 	}
 
 	code := c.Query("code")
 	jwtToken, created, user, err := h.CustomOAuth2Service.GoogleCallback(c.Request.Context(), code)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -78,7 +80,7 @@ func (h *OAuthHandler) Logout(c *gin.Context) {
 			// Revoke both Google OAuth access and refresh tokens
 			if err := h.CustomOAuth2Service.RevokeGoogleToken(c.Request.Context(), claims.AccessToken, claims.RefreshToken); err != nil {
 				// Log error but don't fail logout - token might already be expired/revoked
-				fmt.Printf("Warning: Failed to revoke Google OAuth tokens: %v\n", err)
+				slog.WarnContext(c.Request.Context(), "oauth: failed to revoke Google tokens on logout", "error", err)
 			}
 		}
 	}
@@ -92,7 +94,7 @@ func (h *OAuthHandler) Logout(c *gin.Context) {
 func (h *OAuthHandler) GetCurrentUser(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		c.Error(apperr.Unauthorized("unauthorized", "User not authenticated"))
 		return
 	}
 
@@ -3,6 +3,7 @@ package service
 import (
 	"Travel_Sync/internal/user/entity"
 	"Travel_Sync/internal/user/service"
+	"context"
 	"errors"
 
 	"gorm.io/gorm"
@@ -18,12 +19,18 @@ func NewAuthService(userService *service.UserService) *AuthService {
 
 // Get user by email or create a new user if not exists
 // Returns: user, created(bool), error
-func (authService *AuthService) GetOrCreateUser(email string) (*entity.User, bool, error) {
-    user, err := authService.UserService.GetUserByEmail(email)
+func (authService *AuthService) GetOrCreateUser(ctx context.Context, email string) (*entity.User, bool, error) {
+    user, err := authService.UserService.GetUserByEmail(ctx, email)
 
     if err != nil || user == nil {
         if errors.Is(err, gorm.ErrRecordNotFound) {
-            user, err = authService.UserService.CreateUser(email)
+            // Account soft-deleted within the retention window: bring it back
+            if restored, rerr := authService.UserService.RestoreByEmail(ctx, email); rerr == nil {
+                return restored, false, nil
+            } else if !errors.Is(rerr, gorm.ErrRecordNotFound) {
+                return nil, false, rerr
+            }
+            user, err = authService.UserService.CreateUser(ctx, email)
             if err != nil {
                 return nil, false, err
             }
@@ -3,16 +3,20 @@ package service
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"strings"
 
+	"Travel_Sync/internal/apperr"
 	"Travel_Sync/internal/user/entity"
 	"golang.org/x/oauth2"
 )
 
+// ErrNotStudentEmail rejects Google accounts outside the student domain
+var ErrNotStudentEmail = apperr.Forbidden("not_student_email", "login with your Scaler Student Email")
+
 type CustomOAuth2Service struct {
 	OAuthConfig *oauth2.Config
 	AuthService *AuthService
@@ -40,11 +44,8 @@ func (service *CustomOAuth2Service) GoogleCallback(ctx context.Context, code str
         return "", false, nil, fmt.Errorf("token exchange failed: %w", err)
 	}
 
-	// Debug: Log token information
-	fmt.Printf("DEBUG: Access Token: %s\n", token.AccessToken)
-	fmt.Printf("DEBUG: Refresh Token: %s\n", token.RefreshToken)
-	fmt.Printf("DEBUG: Token Type: %s\n", token.TokenType)
-	fmt.Printf("DEBUG: Expiry: %v\n", token.Expiry)
+	// Never log the tokens themselves
+	slog.DebugContext(ctx, "oauth: exchanged code", "expiry", token.Expiry)
 
 	//Fetch User Info from google
 	client := service.OAuthConfig.Client(ctx, token)
@@ -69,10 +70,10 @@ func (service *CustomOAuth2Service) GoogleCallback(ctx context.Context, code str
 	}
 
 	if ExtractDomain(googleUser.Email) != "sst.scaler.com" {
-        return "", false, nil, errors.New("login with your Scaler Student Email")
+        return "", false, nil, ErrNotStudentEmail
 	}
 
-    user, created, err := service.AuthService.GetOrCreateUser(googleUser.Email)
+    user, created, err := service.AuthService.GetOrCreateUser(ctx, googleUser.Email)
 	if err != nil {
         return "", false, nil, err
 	}
@@ -90,26 +91,21 @@ func (service *CustomOAuth2Service) GoogleCallback(ctx context.Context, code str
 func (service *CustomOAuth2Service) RevokeGoogleToken(ctx context.Context, accessToken string, refreshToken string) error {
 	// Google's token revocation endpoint
 	revokeURL := "https://oauth2.googleapis.com/revoke"
-	
-	fmt.Printf("DEBUG: Revoking access token: %s\n", accessToken)
-	fmt.Printf("DEBUG: Revoking refresh token: %s\n", refreshToken)
-	
+
 	// Revoke access token if provided
 	if accessToken != "" {
 		if err := service.revokeSingleToken(ctx, revokeURL, accessToken); err != nil {
-			fmt.Printf("DEBUG: Failed to revoke access token: %v\n", err)
 			return fmt.Errorf("failed to revoke access token: %w", err)
 		}
-		fmt.Printf("DEBUG: Successfully revoked access token\n")
+		slog.DebugContext(ctx, "oauth: revoked access token")
 	}
 	
 	// Revoke refresh token if provided
 	if refreshToken != "" {
 		if err := service.revokeSingleToken(ctx, revokeURL, refreshToken); err != nil {
-			fmt.Printf("DEBUG: Failed to revoke refresh token: %v\n", err)
 			return fmt.Errorf("failed to revoke refresh token: %w", err)
 		}
-		fmt.Printf("DEBUG: Successfully revoked refresh token\n")
+		slog.DebugContext(ctx, "oauth: revoked refresh token")
 	}
 
 	return nil
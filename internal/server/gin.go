@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"Travel_Sync/internal/config"
+	"Travel_Sync/internal/metrics"
 	"Travel_Sync/internal/middleware"
 
 	"github.com/gin-gonic/gin"
@@ -12,8 +13,14 @@ import (
 
 func NewGinRouter() *gin.Engine {
 	r := gin.New()
-	r.Use(gin.Recovery())
-	r.Use(gin.Logger())
+	// Tags the request with an ID before anything logs, then logs it as one JSON line
+	r.Use(middleware.RequestID())
+	r.Use(middleware.RequestLogger())
+	r.Use(middleware.Metrics())
+	// Renders errors recorded with c.Error by everything registered after it
+	r.Use(middleware.ErrorRenderer())
+	// Panics become 500s that the middlewares above render, log and count
+	r.Use(middleware.Recovery())
 
 	// Set Gin mode via env
 	if cfg := config.LoadConfig(); cfg.GinMode != "" {
@@ -25,12 +32,19 @@ func NewGinRouter() *gin.Engine {
 		_ = r.SetTrustedProxies(appCfg.TrustedProxies)
 	}
 
+	// Prometheus metrics, registered ahead of CORS and rate limiting so scrapes are never
+	// throttled; METRICS_TOKEN, when set, must be sent as a bearer token
+	r.GET("/metrics", metrics.Handler(appCfg.MetricsToken))
+
 	// Add CORS middleware
 	r.Use(middleware.SetupCORS(appCfg))
 
 	// Add global rate limiting
 	r.Use(middleware.GeneralRateLimiter())
 
+	// Per-request deadline for database work, tighter or looser per route
+	r.Use(middleware.QueryTimeout(appCfg.QueryTimeout, appCfg.QueryTimeouts))
+
 	// Add health check endpoint (no authentication required)
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
@@ -0,0 +1,101 @@
+package timezone
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FallbackZone is used when no default zone has been configured
+const FallbackZone = "Asia/Kolkata"
+
+// Local date-time layouts accepted when the input carries no offset
+var localLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+}
+
+var (
+	defaultMu  sync.RWMutex
+	defaultLoc = mustLoad(FallbackZone)
+)
+
+// SetDefault sets the zone used for users who have not picked one. Call once at startup.
+func SetDefault(name string) error {
+	loc, err := Load(name)
+	if err != nil {
+		return err
+	}
+	defaultMu.Lock()
+	defaultLoc = loc
+	defaultMu.Unlock()
+	return nil
+}
+
+// Default returns the campus/default zone
+func Default() *time.Location {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultLoc
+}
+
+// Load resolves an IANA zone name such as "Asia/Kolkata"
+func Load(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("timezone is empty")
+	}
+	// time.LoadLocation treats "Local" as the server zone, which is never what a user means
+	if name == "Local" {
+		return nil, errors.New("invalid timezone")
+	}
+	return time.LoadLocation(name)
+}
+
+// IsValid reports whether name is a loadable IANA zone
+func IsValid(name string) bool {
+	_, err := Load(name)
+	return err == nil
+}
+
+// Resolve returns the first valid zone among names, or the default zone
+func Resolve(names ...string) *time.Location {
+	for _, n := range names {
+		if n == "" {
+			continue
+		}
+		if loc, err := Load(n); err == nil {
+			return loc
+		}
+	}
+	return Default()
+}
+
+// ParseDateTime parses a departure time. RFC3339 input with Z or an explicit offset
+// is taken as is; input without an offset is interpreted in loc. The result is in UTC.
+func ParseDateTime(value string, loc *time.Location) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+	if loc == nil {
+		loc = Default()
+	}
+	for _, layout := range localLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, errors.New("invalid date-time, expected RFC3339 (e.g. 2025-10-01T14:30:00+05:30) or local time (2025-10-01T14:30)")
+}
+
+func mustLoad(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
@@ -1,17 +1,47 @@
 package entity
 
-import "time"
+import (
+	"time"
 
+	"gorm.io/gorm"
+)
+
+// TravelTicket is a rider's trip. Candidate lookups are served by partial indexes on open,
+// live tickets, declared in the SQL migrations rather than here.
 type TravelTicket struct {
-	ID           int64     `gorm:"primaryKey;autoIncrement;not null" json:"id"`
-	Source       string    `gorm:"size:255;not null" json:"source"`
-	Destination  string    `gorm:"size:255;not null" json:"destination"`
-	EmptySeats   int       `gorm:"not null" json:"empty_seats"`
-	DepartureAt  time.Time `gorm:"type:timestamptz;not null" json:"departure_at"`
-	TimeDiffMins int       `gorm:"not null" json:"time_diff_mins"`
-	UserID       int64     `gorm:"not null" json:"user_id"`
-	PhoneNumber  string    `gorm:"size:15;not null" json:"phone_number"`
-	Status       string    `gorm:"type:varchar(20);not null;default:open" json:"status"`
-	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	ID           int64          `gorm:"primaryKey;autoIncrement;not null" json:"id"`
+	Source       string         `gorm:"size:255;not null" json:"source"`
+	Destination  string         `gorm:"size:255;not null" json:"destination"`
+	EmptySeats   int            `gorm:"not null" json:"empty_seats"`
+	DepartureAt  time.Time      `gorm:"type:timestamptz;not null" json:"departure_at"`
+	TimeDiffMins int            `gorm:"not null" json:"time_diff_mins"`
+	UserID       int64          `gorm:"not null" json:"user_id"`
+	PhoneNumber  string         `gorm:"size:15;not null" json:"phone_number"`
+	Status       string         `gorm:"type:varchar(20);not null;default:open" json:"status"`
+	CreatedAt    time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+
+	// Set when the ticket was derived from a flight; schedule changes are pushed to linked tickets
+	FlightNumber     string `gorm:"size:16" json:"flight_number,omitempty"`
+	FlightScheduleID *int64 `gorm:"index" json:"flight_schedule_id,omitempty"`
+
+	// Set when the ticket was derived from a train; riders on the same train match strongly
+	TrainNumber string `gorm:"size:16" json:"train_number,omitempty"`
+
+	// Used by the capacity model when matching; an empty vehicle type means any
+	LuggageCount int    `gorm:"not null;default:0" json:"luggage_count"`
+	VehicleType  string `gorm:"size:16" json:"vehicle_type,omitempty"`
+	PartySize    int    `gorm:"not null;default:1" json:"party_size"`
+
+	// "any" or "same_gender"; same-gender tickets only match riders of the owner's gender
+	GenderPreference string `gorm:"size:16;not null;default:any" json:"gender_preference"`
+
+	// Ordered intermediate stops between Source and Destination, e.g. a station on the way to the airport
+	Waypoints []string `gorm:"serializer:json;type:jsonb" json:"waypoints,omitempty"`
+
+	// "share", "offer" or "request"; offers describe the vehicle and what each seat costs
+	Type           string `gorm:"size:16;not null;default:share" json:"type"`
+	VehicleDetails string `gorm:"size:255" json:"vehicle_details,omitempty"`
+	PricePerSeat   int    `gorm:"not null;default:0" json:"price_per_seat,omitempty"` // in rupees
 }
@@ -1,13 +1,16 @@
 package handler
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"strconv"
-	"strings"
-	"time"
 
+	"Travel_Sync/internal/apperr"
+	"Travel_Sync/internal/middleware"
 	"Travel_Sync/internal/travel/models"
 	tservice "Travel_Sync/internal/travel/service"
+	"Travel_Sync/internal/validation"
 
 	"github.com/gin-gonic/gin"
 )
@@ -23,12 +26,12 @@ func NewTravelTicketHandler(svc *tservice.TravelTicketService) *TravelTicketHand
 func parseID(c *gin.Context) (int64, bool) {
 	idStr := c.Param("id")
 	if idStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "id is required"})
+		c.Error(apperr.ErrInvalidID.WithMessage("id is required"))
 		return 0, false
 	}
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil || id <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "invalid id"})
+		c.Error(apperr.ErrInvalidID)
 		return 0, false
 	}
 	return id, true
@@ -40,26 +43,20 @@ func (h *TravelTicketHandler) Create(c *gin.Context) {
 	claims, _ := c.Get("jwt_claims")
 	userID, ok := c.Get("user_id")
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "unauthorized"})
+		c.Error(apperr.ErrUnauthorized)
 		return
 	}
 	_ = claims
 
 	var dto models.TravelTicketCreateDto
 	if err := c.ShouldBindJSON(&dto); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "invalid request body"})
+		c.Error(validation.BindError(err))
 		return
 	}
 
-	// Validate that departure_at is in UTC format
-	if !isValidUTCTimestamp(dto.DepartureAt) {
-		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "departure_at must be in UTC format (RFC3339 with Z suffix)"})
-		return
-	}
-
-	ticket, err := h.Svc.Create(userID.(int64), &dto)
+	ticket, err := h.Svc.Create(c.Request.Context(), userID.(int64), &dto, middleware.GetRequestID(c))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusCreated, gin.H{"success": true, "data": ticket})
@@ -70,9 +67,9 @@ func (h *TravelTicketHandler) GetByID(c *gin.Context) {
 	if !ok {
 		return
 	}
-	ticket, err := h.Svc.GetByID(id)
+	ticket, err := h.Svc.GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": "ticket not found"})
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"success": true, "data": ticket})
@@ -83,18 +80,37 @@ func (h *TravelTicketHandler) GetRecommendations(c *gin.Context) {
 	if !ok {
 		return
 	}
-	result, err := h.Svc.RecommendForTicket(id)
+	result, err := h.Svc.RecommendForTicket(c.Request.Context(), id, c.Query("explain") == "true")
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = apperr.ErrTimeout.WithMessage("recommendations took too long, please try again").Wrap(err)
+		}
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"success": true, "data": result})
 }
 
+// GetCacheStats reports the recommendation cache counters (admin only)
+func (h *TravelTicketHandler) GetCacheStats(c *gin.Context) {
+	stats, ok := h.Svc.Recommendations.Stats()
+	if !ok {
+		c.Error(apperr.NotFound("cache_disabled", "recommendation cache is disabled"))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": stats})
+}
+
+// GetAll lists tickets; ?same_gender=true limits them to riders of the caller's gender
 func (h *TravelTicketHandler) GetAll(c *gin.Context) {
-	tickets, err := h.Svc.GetAll()
+	uid, ok := c.Get("user_id")
+	if !ok {
+		c.Error(apperr.ErrUnauthorized)
+		return
+	}
+	tickets, err := h.Svc.GetAll(c.Request.Context(), toInt64(uid), c.Query("same_gender") == "true")
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "failed to fetch tickets"})
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"success": true, "data": tickets})
@@ -104,7 +120,7 @@ func (h *TravelTicketHandler) GetAll(c *gin.Context) {
 func (h *TravelTicketHandler) GetMyTickets(c *gin.Context) {
 	uid, ok := c.Get("user_id")
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "unauthorized"})
+		c.Error(apperr.ErrUnauthorized)
 		return
 	}
 	var userID int64
@@ -114,13 +130,13 @@ func (h *TravelTicketHandler) GetMyTickets(c *gin.Context) {
 	case float64:
 		userID = int64(v)
 	default:
-		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "invalid user context"})
+		c.Error(apperr.ErrInvalidUserContext)
 		return
 	}
 
-	tickets, err := h.Svc.GetByUser(userID)
+	tickets, err := h.Svc.GetByUser(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"success": true, "data": tickets})
@@ -133,29 +149,19 @@ func (h *TravelTicketHandler) Update(c *gin.Context) {
 	}
 	var dto models.TravelTicketUpdateDto
 	if err := c.ShouldBindJSON(&dto); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "invalid request body"})
-		return
-	}
-
-	// Validate that departure_at is in UTC format if provided
-	if dto.DepartureAt != "" && !isValidUTCTimestamp(dto.DepartureAt) {
-		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "departure_at must be in UTC format (RFC3339 with Z suffix)"})
+		c.Error(validation.BindError(err))
 		return
 	}
 
 	uid, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "unauthorized"})
+		c.Error(apperr.ErrUnauthorized)
 		return
 	}
 	currentUserID := toInt64(uid)
-	ticket, err := h.Svc.Update(currentUserID, id, &dto)
+	ticket, err := h.Svc.Update(c.Request.Context(), currentUserID, id, &dto, middleware.GetRequestID(c))
 	if err != nil {
-		if err.Error() == "forbidden" {
-			c.JSON(http.StatusForbidden, gin.H{"success": false, "error": "forbidden"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"success": true, "data": ticket})
@@ -168,25 +174,55 @@ func (h *TravelTicketHandler) Delete(c *gin.Context) {
 	}
 	uid, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "unauthorized"})
+		c.Error(apperr.ErrUnauthorized)
 		return
 	}
 	currentUserID := toInt64(uid)
-	if err := h.Svc.Delete(currentUserID, id); err != nil {
-		if err.Error() == "forbidden" {
-			c.JSON(http.StatusForbidden, gin.H{"success": false, "error": "forbidden"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "failed to delete ticket"})
+	if err := h.Svc.Delete(c.Request.Context(), currentUserID, id, middleware.GetRequestID(c)); err != nil {
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"success": true, "data": "ticket deleted"})
 }
 
+// GetDeleted returns the authenticated user's soft-deleted tickets
+func (h *TravelTicketHandler) GetDeleted(c *gin.Context) {
+	uid, ok := c.Get("user_id")
+	if !ok {
+		c.Error(apperr.ErrUnauthorized)
+		return
+	}
+	tickets, err := h.Svc.GetDeleted(c.Request.Context(), toInt64(uid))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": tickets})
+}
+
+// Restore undoes a soft delete of one of the user's tickets
+func (h *TravelTicketHandler) Restore(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	uid, exists := c.Get("user_id")
+	if !exists {
+		c.Error(apperr.ErrUnauthorized)
+		return
+	}
+	ticket, err := h.Svc.Restore(c.Request.Context(), toInt64(uid), id, middleware.GetRequestID(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": ticket})
+}
+
 func (h *TravelTicketHandler) GetUserResponses(c *gin.Context) {
 	uid, ok := c.Get("user_id")
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "unauthorized"})
+		c.Error(apperr.ErrUnauthorized)
 		return
 	}
 	userID, ok := uid.(int64)
@@ -195,14 +231,14 @@ func (h *TravelTicketHandler) GetUserResponses(c *gin.Context) {
 		if f, ok2 := uid.(float64); ok2 {
 			userID = int64(f)
 		} else {
-			c.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "invalid user context"})
+			c.Error(apperr.ErrInvalidUserContext)
 			return
 		}
 	}
 
-	responses, err := h.Svc.GetUserResponses(userID)
+	responses, err := h.Svc.GetUserResponses(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"success": true, "data": responses})
@@ -217,17 +253,3 @@ func toInt64(v interface{}) int64 {
 	}
 	return 0
 }
-
-// isValidUTCTimestamp validates that the timestamp string is in UTC format (RFC3339 with Z suffix)
-func isValidUTCTimestamp(timestamp string) bool {
-	if timestamp == "" {
-		return true // Empty is valid for optional fields
-	}
-	// Check if it ends with 'Z' (UTC indicator)
-	if !strings.HasSuffix(timestamp, "Z") {
-		return false
-	}
-	// Try to parse as RFC3339
-	_, err := time.Parse(time.RFC3339, timestamp)
-	return err == nil
-}
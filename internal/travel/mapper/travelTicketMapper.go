@@ -1,19 +1,30 @@
 package mapper
 
 import (
+	"Travel_Sync/internal/timezone"
 	tentity "Travel_Sync/internal/travel/entity"
 	"Travel_Sync/internal/travel/models"
 	uentity "Travel_Sync/internal/user/entity"
-	"time"
 )
 
 func FromCreateDtoToEntity(dto *models.TravelTicketCreateDto, user *uentity.User) (*tentity.TravelTicket, error) {
-	departureAt, err := time.Parse(time.RFC3339, dto.DepartureAt)
+	// Local times are read in the zone given with the request, else the user's zone; stored in UTC
+	departureAt, err := timezone.ParseDateTime(dto.DepartureAt, timezone.Resolve(dto.Timezone, user.Timezone))
 	if err != nil {
 		return nil, err
 	}
-	// Ensure the time is in UTC
-	departureAt = departureAt.UTC()
+	partySize := dto.PartySize
+	if partySize == 0 {
+		partySize = 1
+	}
+	ticketType := dto.Type
+	if ticketType == "" {
+		ticketType = models.TicketTypeShare
+	}
+	genderPreference := dto.GenderPreference
+	if genderPreference == "" {
+		genderPreference = models.GenderPrefAny
+	}
 	return &tentity.TravelTicket{
 		Source:       dto.Source,
 		Destination:  dto.Destination,
@@ -23,10 +34,19 @@ func FromCreateDtoToEntity(dto *models.TravelTicketCreateDto, user *uentity.User
 		UserID:       user.ID,
 		PhoneNumber:  dto.PhoneNumber,
 		Status:       "open",
+		LuggageCount: dto.LuggageCount,
+		VehicleType:  dto.VehicleType,
+		PartySize:    partySize,
+
+		GenderPreference: genderPreference,
+		Waypoints:        dto.Waypoints,
+		Type:             ticketType,
+		VehicleDetails:   dto.VehicleDetails,
+		PricePerSeat:     dto.PricePerSeat,
 	}, nil
 }
 
-func ApplyUpdateDtoToEntity(dto *models.TravelTicketUpdateDto, ticket *tentity.TravelTicket) *tentity.TravelTicket {
+func ApplyUpdateDtoToEntity(dto *models.TravelTicketUpdateDto, ticket *tentity.TravelTicket, user *uentity.User) (*tentity.TravelTicket, error) {
 	if dto.Source != "" {
 		ticket.Source = dto.Source
 	}
@@ -34,9 +54,11 @@ func ApplyUpdateDtoToEntity(dto *models.TravelTicketUpdateDto, ticket *tentity.T
 		ticket.Destination = dto.Destination
 	}
 	if dto.DepartureAt != "" {
-		if t, err := time.Parse(time.RFC3339, dto.DepartureAt); err == nil {
-			ticket.DepartureAt = t.UTC() // Ensure UTC
+		t, err := timezone.ParseDateTime(dto.DepartureAt, timezone.Resolve(dto.Timezone, user.Timezone))
+		if err != nil {
+			return nil, err
 		}
+		ticket.DepartureAt = t // already UTC
 	}
 	if dto.TimeDiffMins != 0 {
 		ticket.TimeDiffMins = dto.TimeDiffMins
@@ -50,20 +72,60 @@ func ApplyUpdateDtoToEntity(dto *models.TravelTicketUpdateDto, ticket *tentity.T
 	if dto.Status != "" {
 		ticket.Status = dto.Status
 	}
-	return ticket
+	if dto.LuggageCount != nil {
+		ticket.LuggageCount = *dto.LuggageCount
+	}
+	if dto.VehicleType != nil {
+		ticket.VehicleType = *dto.VehicleType
+		if ticket.VehicleType == "any" {
+			ticket.VehicleType = ""
+		}
+	}
+	if dto.PartySize != 0 {
+		ticket.PartySize = dto.PartySize
+	}
+	if dto.GenderPreference != "" {
+		ticket.GenderPreference = dto.GenderPreference
+	}
+	if dto.Waypoints != nil {
+		ticket.Waypoints = *dto.Waypoints
+	}
+	if dto.Type != "" {
+		ticket.Type = dto.Type
+	}
+	if dto.VehicleDetails != "" {
+		ticket.VehicleDetails = dto.VehicleDetails
+	}
+	if dto.PricePerSeat != nil {
+		ticket.PricePerSeat = *dto.PricePerSeat
+	}
+	return ticket, nil
 }
 
+// ToUserResponseDto renders the ticket with date and time in the user's zone
 func ToUserResponseDto(ticket *tentity.TravelTicket, user *uentity.User) *models.TravelTicketUserResponseDto {
+	loc := timezone.Resolve(user.Timezone)
+	local := ticket.DepartureAt.In(loc)
 	return &models.TravelTicketUserResponseDto{
 		ID:           ticket.ID,
 		StudentName:  user.Name,
 		StudentBatch: user.Batch,
 		Source:       ticket.Source,
 		Destination:  ticket.Destination,
-		Date:         ticket.DepartureAt.Format("2006-01-02"),
-		Time:         ticket.DepartureAt.Format("15:04"),
+		Date:         local.Format("2006-01-02"),
+		Time:         local.Format("15:04"),
+		Timezone:     loc.String(),
 		EmptySeats:   ticket.EmptySeats,
 		PhoneNumber:  ticket.PhoneNumber,
+		LuggageCount: ticket.LuggageCount,
+		VehicleType:  ticket.VehicleType,
+		PartySize:    ticket.PartySize,
+
+		GenderPreference: ticket.GenderPreference,
+		Waypoints:        ticket.Waypoints,
+		Type:             ticket.Type,
+		VehicleDetails:   ticket.VehicleDetails,
+		PricePerSeat:     ticket.PricePerSeat,
 	}
 }
 
@@ -0,0 +1,69 @@
+package models
+
+// Vehicle types a ticket can ask for. An empty preference means any vehicle.
+const (
+	VehicleAuto  = "auto"
+	VehicleSedan = "sedan"
+	VehicleSUV   = "suv"
+)
+
+// VehicleCapacity is how many passengers and large bags fit in a vehicle
+type VehicleCapacity struct {
+	Seats   int
+	Luggage int
+}
+
+var VehicleCapacities = map[string]VehicleCapacity{
+	VehicleAuto:  {Seats: 3, Luggage: 2},
+	VehicleSedan: {Seats: 4, Luggage: 3},
+	VehicleSUV:   {Seats: 6, Luggage: 5},
+}
+
+// vehiclesBySize lists vehicle types from smallest to largest
+var vehiclesBySize = []string{VehicleAuto, VehicleSedan, VehicleSUV}
+
+func IsVehicleType(v string) bool {
+	_, ok := VehicleCapacities[v]
+	return ok
+}
+
+// RideLoad is the combined party size and luggage of riders sharing one vehicle. Vehicle is
+// the type every rider accepts, empty while nobody has a preference.
+type RideLoad struct {
+	Seats   int
+	Luggage int
+	Vehicle string
+}
+
+// Add returns the load with one more ticket on board, and false when the riders' vehicle
+// preferences conflict or the combined load no longer fits any acceptable vehicle.
+func (l RideLoad) Add(partySize, luggage int, vehicle string) (RideLoad, bool) {
+	if partySize < 1 {
+		partySize = 1
+	}
+	if vehicle != "" && l.Vehicle != "" && vehicle != l.Vehicle {
+		return l, false
+	}
+	next := RideLoad{Seats: l.Seats + partySize, Luggage: l.Luggage + luggage, Vehicle: l.Vehicle}
+	if next.Vehicle == "" {
+		next.Vehicle = vehicle
+	}
+	if next.SmallestVehicle() == "" {
+		return l, false
+	}
+	return next, true
+}
+
+// SmallestVehicle returns the smallest acceptable vehicle the load fits in, or "" if none
+func (l RideLoad) SmallestVehicle() string {
+	for _, v := range vehiclesBySize {
+		if l.Vehicle != "" && v != l.Vehicle {
+			continue
+		}
+		c := VehicleCapacities[v]
+		if l.Seats <= c.Seats && l.Luggage <= c.Luggage {
+			return v
+		}
+	}
+	return ""
+}
@@ -21,6 +21,65 @@ var (
 	}
 )
 
+// Trip directions relative to campus
+const (
+	DirectionOutbound = "outbound" // Hostel → Home
+	DirectionReturn   = "return"   // Home → Hostel
+)
+
+// TripDirection classifies a trip: anything ending at a hostel is a return trip,
+// everything else is outbound.
+func TripDirection(source, destination string) string {
+	if IsHostel(destination) {
+		return DirectionReturn
+	}
+	return DirectionOutbound
+}
+
+// Ticket types: riders splitting a cab, riders offering seats in their car or booked cab,
+// and riders looking for a seat in someone else's ride
+const (
+	TicketTypeShare   = "share"
+	TicketTypeOffer   = "offer"
+	TicketTypeRequest = "request"
+)
+
+// TypesCompatible reports whether tickets of the two types can ride together: offers pair with
+// anyone who is not offering, and sharers pair with each other.
+func TypesCompatible(a, b string) bool {
+	switch {
+	case a == TicketTypeOffer:
+		return b != TicketTypeOffer
+	case b == TicketTypeOffer:
+		return true
+	}
+	return a == TicketTypeShare && b == TicketTypeShare
+}
+
+// Co-traveller gender preferences on a ticket
+const (
+	GenderPrefAny  = "any"
+	GenderPrefSame = "same_gender"
+)
+
+// GenderCompatible reports whether two riders may share a ride. A same-gender preference on
+// either side needs both genders to be known and equal, so it is enforced in both directions.
+func GenderCompatible(prefA, genderA, prefB, genderB string) bool {
+	if prefA != GenderPrefSame && prefB != GenderPrefSame {
+		return true
+	}
+	return genderA != "" && genderA == genderB
+}
+
+// HostelNames returns the hostel names as a slice, e.g. for SQL IN clauses
+func HostelNames() []string {
+	names := make([]string, 0, len(Hostels))
+	for h := range Hostels {
+		names = append(names, h)
+	}
+	return names
+}
+
 func IsHostel(loc string) bool {
 	_, ok := Hostels[loc]
 	return ok
@@ -57,4 +116,4 @@ func AreNearbyHostels(a, b string) bool {
 // IsValidLocation checks if a location exists in any of the predefined maps
 func IsValidLocation(loc string) bool {
 	return IsHostel(loc) || IsAirportTerminal(loc) || IsRailwayStation(loc)
-}
\ No newline at end of file
+}
@@ -0,0 +1,113 @@
+package models
+
+import "math"
+
+// MaxWaypoints is the most intermediate stops a ticket may have
+const MaxWaypoints = 3
+
+// LatLng is a point in decimal degrees
+type LatLng struct {
+	Lat float64
+	Lng float64
+}
+
+// Coordinates of the predefined locations, used to price detours between stops
+var Coordinates = map[string]LatLng{
+	"Uniworld-1": {Lat: 12.8452, Lng: 77.6602},
+	"Uniworld-2": {Lat: 12.8460, Lng: 77.6625},
+
+	"Kempegowda International Airport Terminal-1": {Lat: 13.1989, Lng: 77.7068},
+	"Kempegowda International Airport Terminal-2": {Lat: 13.2007, Lng: 77.7105},
+
+	"KSR SBC Bengaluru Junction Railway Station": {Lat: 12.9784, Lng: 77.5694},
+	"SMVT Bengaluru Railway station":             {Lat: 12.9916, Lng: 77.6635},
+	"Krishnarajapuram Railway Station":           {Lat: 13.0007, Lng: 77.6780},
+	"Yesvantpur Junction Railway station":        {Lat: 13.0233, Lng: 77.5501},
+	"Banglore Cantonment Railway Station":        {Lat: 12.9939, Lng: 77.5980},
+	"Bengaluru East Railway Station":             {Lat: 12.9973, Lng: 77.6233},
+}
+
+// Route returns the ordered stops of a trip: source, waypoints, destination
+func Route(source string, waypoints []string, destination string) []string {
+	stops := make([]string, 0, len(waypoints)+2)
+	stops = append(stops, source)
+	stops = append(stops, waypoints...)
+	return append(stops, destination)
+}
+
+// DistanceKm is the great-circle distance between two known locations
+func DistanceKm(a, b string) (float64, bool) {
+	pa, ok := Coordinates[a]
+	if !ok {
+		return 0, false
+	}
+	pb, ok := Coordinates[b]
+	if !ok {
+		return 0, false
+	}
+	const earthRadiusKm = 6371.0
+	rad := math.Pi / 180
+	dLat := (pb.Lat - pa.Lat) * rad
+	dLng := (pb.Lng - pa.Lng) * rad
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(pa.Lat*rad)*math.Cos(pb.Lat*rad)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(h)), true
+}
+
+// DetourKm is the extra distance the outer route has to cover to serve every stop of the
+// inner route in order. Stops already on the outer route cost nothing; any other stop is
+// inserted into the cheapest leg that keeps the order. Returns false when the inner stops
+// cannot be served in order or a location has no coordinates.
+func DetourKm(inner, outer []string) (float64, bool) {
+	total := 0.0
+	next := 0 // first outer index the next stop may match
+	leg := 0  // first outer leg (outer[j] → outer[j+1]) the next stop may be inserted into
+	for _, stop := range inner {
+		if at := indexFrom(outer, stop, next); at >= 0 {
+			next, leg = at+1, at
+			continue
+		}
+		best, bestLeg := math.Inf(1), -1
+		for j := leg; j+1 < len(outer); j++ {
+			toStop, ok1 := DistanceKm(outer[j], stop)
+			fromStop, ok2 := DistanceKm(stop, outer[j+1])
+			direct, ok3 := DistanceKm(outer[j], outer[j+1])
+			if !ok1 || !ok2 || !ok3 {
+				return 0, false
+			}
+			if extra := toStop + fromStop - direct; extra < best {
+				best, bestLeg = extra, j
+			}
+		}
+		if bestLeg < 0 {
+			return 0, false
+		}
+		total += best
+		next, leg = bestLeg+1, bestLeg
+	}
+	return total, true
+}
+
+// SharedRouteDetourKm is the smaller detour of riding either route inside the other
+func SharedRouteDetourKm(a, b []string) (float64, bool) {
+	ab, okAB := DetourKm(a, b)
+	ba, okBA := DetourKm(b, a)
+	switch {
+	case okAB && okBA:
+		return math.Min(ab, ba), true
+	case okAB:
+		return ab, true
+	case okBA:
+		return ba, true
+	}
+	return 0, false
+}
+
+func indexFrom(stops []string, stop string, from int) int {
+	for i := from; i < len(stops); i++ {
+		if stops[i] == stop {
+			return i
+		}
+	}
+	return -1
+}
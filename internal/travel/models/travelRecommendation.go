@@ -4,6 +4,13 @@ import (
 	"time"
 )
 
+// AffinityWeights are score boosts for social signals between two riders
+type AffinityWeights struct {
+	SameBatch   float64
+	Favourite   float64
+	CoTraveller float64
+}
+
 type MinimalUser struct {
 	Name  string `json:"name"`
 	Batch string `json:"batch"`
@@ -20,8 +27,35 @@ type PublicTicket struct {
 	TimeDiffMins int       `json:"time_diff_mins"`
 	PhoneNumber  string    `json:"phone_number"`
 	Status       string    `json:"status"`
+	LuggageCount int       `json:"luggage_count"`
+	VehicleType  string    `json:"vehicle_type,omitempty"`
+	PartySize    int       `json:"party_size"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
+
+	GenderPreference string   `json:"gender_preference"`
+	Waypoints        []string `json:"waypoints,omitempty"`
+	Type             string   `json:"type"`
+	VehicleDetails   string   `json:"vehicle_details,omitempty"`
+	PricePerSeat     int      `json:"price_per_seat,omitempty"`
+}
+
+// ScoreFactor is one step of the scoring, applied in order starting from ScoreBreakdown.Base
+type ScoreFactor struct {
+	Name   string  `json:"name"`   // time_difference, source, destination, detour, same_train, same_batch, favourite, co_traveller
+	Effect string  `json:"effect"` // "subtract", "multiply" or "add"
+	Value  float64 `json:"value"`
+	Detail string  `json:"detail,omitempty"`
+}
+
+// ScoreBreakdown explains how a candidate's score was reached. The score never drops below 0.
+// Bonuses are only added to a score above 0 and are not capped, so a boosted score can exceed
+// 100; a candidate's bonuses count towards Affinity either way.
+type ScoreBreakdown struct {
+	Base     float64       `json:"base"`
+	Factors  []ScoreFactor `json:"factors"`
+	Score    float64       `json:"score"`
+	Affinity float64       `json:"affinity"` // sum of the bonuses the candidate qualifies for; breaks score ties
 }
 
 type ScoredTicket struct {
@@ -31,10 +65,16 @@ type ScoredTicket struct {
 	Time        string       `json:"time"`
 	User        MinimalUser  `json:"user"`
 	CandidateID int64        `json:"-"` // internal use only, not exposed
+	Affinity    float64      `json:"-"` // secondary sort key, see ScoreBreakdown.Affinity
+
+	Explain *ScoreBreakdown `json:"explain,omitempty"` // only with ?explain=true
 }
 
 type RecommendationResult struct {
 	BestMatch         *ScoredTicket  `json:"best_match"`
 	BestGroup         []ScoredTicket `json:"best_group"`
 	OtherAlternatives []ScoredTicket `json:"other_alternatives"`
+	AvailableRides    []ScoredTicket `json:"available_rides"`         // seats offered by others, for request and share tickets
+	Timezone          string         `json:"timezone"`                // zone of the date and time fields
+	GroupVehicle      string         `json:"group_vehicle,omitempty"` // smallest vehicle that fits the best group with the ticket owner
 }
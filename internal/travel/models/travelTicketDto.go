@@ -1,22 +1,67 @@
 package models
 
 type TravelTicketCreateDto struct {
-	Source       string `json:"source" binding:"required"`
-	Destination  string `json:"destination" binding:"required"`
-	DepartureAt  string `json:"departure_at" binding:"required"` // RFC3339 UTC format e.g., 2025-10-01T14:30:00Z (must end with Z)
+	Source       string `json:"source" binding:"required_without_all=FlightNumber TrainNumber,omitempty,location"`
+	Destination  string `json:"destination" binding:"required_without_all=FlightNumber TrainNumber,omitempty,location"`
+	DepartureAt  string `json:"departure_at" binding:"required_without_all=FlightNumber TrainNumber,omitempty,departure_time"` // RFC3339 with Z or offset e.g. 2025-10-01T14:30:00+05:30, or local time 2025-10-01T14:30 in Timezone
 	TimeDiffMins int    `json:"time_diff_mins" binding:"required,min=0,max=720"`
 	EmptySeats   int    `json:"empty_seats" binding:"required,min=1,max=10"`
-	PhoneNumber  string `json:"phone_number" binding:"required"`
+	PhoneNumber  string `json:"phone_number" binding:"required,phone"`
+	Timezone     string `json:"timezone" binding:"omitempty,tz"` // optional IANA zone for local departure_at, defaults to the user's zone
+
+	// With a flight number only the hostel end is needed: the terminal and departure time
+	// are derived from the flight schedule for FlightDate (local date, 2006-01-02)
+	FlightNumber string `json:"flight_number"`
+	FlightDate   string `json:"flight_date" binding:"required_with=FlightNumber,omitempty,datetime=2006-01-02"`
+
+	// Same for trains: the station (if not given) and time come from the timetable for TrainDate
+	TrainNumber string `json:"train_number"`
+	TrainDate   string `json:"train_date" binding:"required_with=TrainNumber,omitempty,datetime=2006-01-02"`
+
+	// Optional; party size defaults to 1 (just the ticket owner)
+	LuggageCount int    `json:"luggage_count" binding:"omitempty,min=0,max=10"`
+	VehicleType  string `json:"vehicle_type" binding:"omitempty,oneof=auto sedan suv"`
+	PartySize    int    `json:"party_size" binding:"omitempty,min=1,max=6"`
+
+	// "any" (default) or "same_gender"; the latter needs a gender on the user's profile
+	GenderPreference string `json:"gender_preference" binding:"omitempty,oneof=any same_gender"`
+
+	// Ordered stops between source and destination (at most MaxWaypoints)
+	Waypoints []string `json:"waypoints" binding:"omitempty,max=3,dive,location"`
+
+	// "share" (default), "offer" or "request"; with an offer, empty_seats are the seats offered
+	Type           string `json:"type" binding:"omitempty,oneof=share offer request"`
+	VehicleDetails string `json:"vehicle_details" binding:"required_if=Type offer,max=255"`
+	PricePerSeat   int    `json:"price_per_seat" binding:"min=0,max=10000"`
 }
 
 type TravelTicketUpdateDto struct {
-	Source       string `json:"source"`
-	Destination  string `json:"destination"`
-	DepartureAt  string `json:"departure_at"` // RFC3339 UTC format e.g., 2025-10-01T14:30:00Z (must end with Z), optional
-	TimeDiffMins int    `json:"time_diff_mins"`
-	EmptySeats   int    `json:"empty_seats"`
-	PhoneNumber  string `json:"phone_number"`
-	Status       string `json:"status"` // "open" or "closed"
+	Source       string `json:"source" binding:"omitempty,location"`
+	Destination  string `json:"destination" binding:"omitempty,location"`
+	DepartureAt  string `json:"departure_at" binding:"omitempty,departure_time"` // same formats as TravelTicketCreateDto, optional
+	TimeDiffMins int    `json:"time_diff_mins" binding:"omitempty,min=0,max=720"`
+	EmptySeats   int    `json:"empty_seats" binding:"omitempty,min=1,max=10"`
+	PhoneNumber  string `json:"phone_number" binding:"omitempty,phone"`
+	Status       string `json:"status" binding:"omitempty,oneof=open closed"`
+	Timezone     string `json:"timezone" binding:"omitempty,tz"`
+	FlightNumber string `json:"flight_number"` // re-derive terminal and time from this flight
+	FlightDate   string `json:"flight_date" binding:"required_with=FlightNumber,omitempty,datetime=2006-01-02"`
+	TrainNumber  string `json:"train_number"` // re-derive station and time from this train
+	TrainDate    string `json:"train_date" binding:"required_with=TrainNumber,omitempty,datetime=2006-01-02"`
+
+	// Pointers so that 0 bags can be set; vehicle type "any" clears the preference
+	LuggageCount *int    `json:"luggage_count" binding:"omitempty,min=0,max=10"`
+	VehicleType  *string `json:"vehicle_type" binding:"omitempty,oneof=auto sedan suv any"`
+	PartySize    int     `json:"party_size" binding:"omitempty,min=1,max=6"`
+
+	GenderPreference string `json:"gender_preference" binding:"omitempty,oneof=any same_gender"`
+
+	// Replaces the stops when present; an empty list removes them
+	Waypoints *[]string `json:"waypoints" binding:"omitempty,max=3,dive,location"`
+
+	Type           string `json:"type" binding:"omitempty,oneof=share offer request"`
+	VehicleDetails string `json:"vehicle_details" binding:"max=255"`
+	PricePerSeat   *int   `json:"price_per_seat" binding:"omitempty,min=0,max=10000"`
 }
 
 type TravelTicketUserResponseDto struct {
@@ -25,8 +70,18 @@ type TravelTicketUserResponseDto struct {
 	StudentBatch string `json:"student_batch"`
 	Source       string `json:"source"`
 	Destination  string `json:"destination"`
-	Date         string `json:"date"` // 2006-01-02
-	Time         string `json:"time"` // 15:04
+	Date         string `json:"date"` // 2006-01-02, in Timezone
+	Time         string `json:"time"` // 15:04, in Timezone
+	Timezone     string `json:"timezone"`
 	EmptySeats   int    `json:"empty_seats"`
 	PhoneNumber  string `json:"phone_number"`
+	LuggageCount int    `json:"luggage_count"`
+	VehicleType  string `json:"vehicle_type,omitempty"`
+	PartySize    int    `json:"party_size"`
+
+	GenderPreference string   `json:"gender_preference"`
+	Waypoints        []string `json:"waypoints,omitempty"`
+	Type             string   `json:"type"`
+	VehicleDetails   string   `json:"vehicle_details,omitempty"`
+	PricePerSeat     int      `json:"price_per_seat,omitempty"`
 }
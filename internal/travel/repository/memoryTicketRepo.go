@@ -0,0 +1,279 @@
+package repository
+
+import (
+	"Travel_Sync/internal/travel/entity"
+	"Travel_Sync/internal/travel/models"
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MemoryTicketRepo is an in-process TravelTicketRepository with the same semantics as the
+// Postgres one: soft deletes, column defaults and gorm.ErrRecordNotFound for missing rows.
+// Only the candidate queries look at the context, returning its error once it is done.
+// Tickets are copied in and out, so callers never share state with the store.
+type MemoryTicketRepo struct {
+	mu      sync.Mutex
+	tickets map[int64]entity.TravelTicket
+	nextID  int64
+}
+
+func NewMemoryTicketRepo() *MemoryTicketRepo {
+	return &MemoryTicketRepo{tickets: make(map[int64]entity.TravelTicket), nextID: 1}
+}
+
+func (r *MemoryTicketRepo) Create(ctx context.Context, ticket *entity.TravelTicket) (*entity.TravelTicket, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if ticket.ID == 0 {
+		ticket.ID = r.nextID
+	} else if _, exists := r.tickets[ticket.ID]; exists {
+		return nil, gorm.ErrDuplicatedKey
+	}
+	if ticket.ID >= r.nextID {
+		r.nextID = ticket.ID + 1
+	}
+	// Column defaults, as applied by the database
+	if ticket.Status == "" {
+		ticket.Status = "open"
+	}
+	if ticket.PartySize == 0 {
+		ticket.PartySize = 1
+	}
+	if ticket.GenderPreference == "" {
+		ticket.GenderPreference = models.GenderPrefAny
+	}
+	if ticket.Type == "" {
+		ticket.Type = models.TicketTypeShare
+	}
+	now := time.Now()
+	if ticket.CreatedAt.IsZero() {
+		ticket.CreatedAt = now
+	}
+	ticket.UpdatedAt = now
+	r.tickets[ticket.ID] = copyTicket(*ticket)
+	return ticket, nil
+}
+
+func (r *MemoryTicketRepo) GetByID(ctx context.Context, id int64) (*entity.TravelTicket, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t, ok := r.tickets[id]
+	if !ok || t.DeletedAt.Valid {
+		return nil, gorm.ErrRecordNotFound
+	}
+	t = copyTicket(t)
+	return &t, nil
+}
+
+func (r *MemoryTicketRepo) GetAll(ctx context.Context) ([]entity.TravelTicket, error) {
+	return r.find(func(t *entity.TravelTicket) bool { return true }), nil
+}
+
+// Update saves every field like gorm's Save, creating the ticket when it does not exist
+func (r *MemoryTicketRepo) Update(ctx context.Context, ticket *entity.TravelTicket) (*entity.TravelTicket, error) {
+	if ticket.ID == 0 {
+		return r.Create(ctx, ticket)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ticket.UpdatedAt = time.Now()
+	r.tickets[ticket.ID] = copyTicket(*ticket)
+	if ticket.ID >= r.nextID {
+		r.nextID = ticket.ID + 1
+	}
+	return ticket, nil
+}
+
+// Delete soft-deletes the ticket; deleting a missing ticket is not an error
+func (r *MemoryTicketRepo) Delete(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if t, ok := r.tickets[id]; ok && !t.DeletedAt.Valid {
+		t.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+		r.tickets[id] = t
+	}
+	return nil
+}
+
+func (r *MemoryTicketRepo) GetDeletedByID(ctx context.Context, id int64) (*entity.TravelTicket, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t, ok := r.tickets[id]
+	if !ok || !t.DeletedAt.Valid {
+		return nil, gorm.ErrRecordNotFound
+	}
+	t = copyTicket(t)
+	return &t, nil
+}
+
+func (r *MemoryTicketRepo) GetDeletedByUserID(ctx context.Context, userID int64) ([]entity.TravelTicket, error) {
+	tickets := r.findUnscoped(func(t *entity.TravelTicket) bool {
+		return t.UserID == userID && t.DeletedAt.Valid
+	})
+	sort.SliceStable(tickets, func(i, j int) bool { return tickets[i].DeletedAt.Time.After(tickets[j].DeletedAt.Time) })
+	return tickets, nil
+}
+
+func (r *MemoryTicketRepo) GetByUserIDIncludingDeleted(ctx context.Context, userID int64) ([]entity.TravelTicket, error) {
+	return r.findUnscoped(func(t *entity.TravelTicket) bool { return t.UserID == userID }), nil
+}
+
+func (r *MemoryTicketRepo) Restore(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if t, ok := r.tickets[id]; ok {
+		t.DeletedAt = gorm.DeletedAt{}
+		r.tickets[id] = t
+	}
+	return nil
+}
+
+func (r *MemoryTicketRepo) PurgeDeletedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	return r.purge(func(t *entity.TravelTicket) bool {
+		return t.DeletedAt.Valid && t.DeletedAt.Time.Before(cutoff)
+	}), nil
+}
+
+func (r *MemoryTicketRepo) PurgeByUserIDs(ctx context.Context, userIDs []int64) (int64, error) {
+	users := make(map[int64]bool, len(userIDs))
+	for _, id := range userIDs {
+		users[id] = true
+	}
+	return r.purge(func(t *entity.TravelTicket) bool { return users[t.UserID] }), nil
+}
+
+func (r *MemoryTicketRepo) GetByUserID(ctx context.Context, userID int64) ([]entity.TravelTicket, error) {
+	return r.find(func(t *entity.TravelTicket) bool { return t.UserID == userID }), nil
+}
+
+func (r *MemoryTicketRepo) GetByFlightScheduleID(ctx context.Context, scheduleID int64) ([]entity.TravelTicket, error) {
+	return r.find(func(t *entity.TravelTicket) bool {
+		return t.FlightScheduleID != nil && *t.FlightScheduleID == scheduleID
+	}), nil
+}
+
+func (r *MemoryTicketRepo) CountByUserID(ctx context.Context, userID int64) (int64, error) {
+	tickets, _ := r.GetByUserID(ctx, userID)
+	return int64(len(tickets)), nil
+}
+
+func (r *MemoryTicketRepo) ExistsForUserOnDate(ctx context.Context, userID int64, dayStart, dayEnd time.Time, direction string, excludeID *int64) (bool, error) {
+	found := r.find(func(t *entity.TravelTicket) bool {
+		if t.UserID != userID || t.DepartureAt.Before(dayStart) || !t.DepartureAt.Before(dayEnd) {
+			return false
+		}
+		if excludeID != nil && t.ID == *excludeID {
+			return false
+		}
+		return models.IsHostel(t.Destination) == (direction == models.DirectionReturn)
+	})
+	return len(found) > 0, nil
+}
+
+func (r *MemoryTicketRepo) GetCandidatesSameDateOutbound(ctx context.Context, destination string, dayStart time.Time, excludeID int64) ([]entity.TravelTicket, error) {
+	dayEnd := dayStart.Add(24 * time.Hour)
+	return r.find(func(t *entity.TravelTicket) bool {
+		return sameEnd(t.Destination, destination) && isOpenCandidate(t, excludeID) &&
+			!t.DepartureAt.Before(dayStart) && t.DepartureAt.Before(dayEnd)
+	}), nil
+}
+
+func (r *MemoryTicketRepo) GetCandidatesSameDateReturn(ctx context.Context, source string, dayStart time.Time, excludeID int64) ([]entity.TravelTicket, error) {
+	dayEnd := dayStart.Add(24 * time.Hour)
+	return r.find(func(t *entity.TravelTicket) bool {
+		return sameEnd(t.Source, source) && isOpenCandidate(t, excludeID) &&
+			!t.DepartureAt.Before(dayStart) && t.DepartureAt.Before(dayEnd)
+	}), nil
+}
+
+func (r *MemoryTicketRepo) GetCandidatesTimeWindowOutbound(ctx context.Context, stops []string, targetTime time.Time, timeWindowBefore, timeWindowAfter time.Duration, excludeID int64) ([]entity.TravelTicket, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return r.inWindow(stops, func(t *entity.TravelTicket) string { return t.Destination }, targetTime, timeWindowBefore, timeWindowAfter, excludeID), nil
+}
+
+func (r *MemoryTicketRepo) GetCandidatesTimeWindowReturn(ctx context.Context, stops []string, targetTime time.Time, timeWindowBefore, timeWindowAfter time.Duration, excludeID int64) ([]entity.TravelTicket, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return r.inWindow(stops, func(t *entity.TravelTicket) string { return t.Source }, targetTime, timeWindowBefore, timeWindowAfter, excludeID), nil
+}
+
+// inWindow mirrors onRoute: the end (terminals interchangeable) is one of stops, or a
+// waypoint is exactly one of them
+func (r *MemoryTicketRepo) inWindow(stops []string, end func(*entity.TravelTicket) string, targetTime time.Time, before, after time.Duration, excludeID int64) []entity.TravelTicket {
+	windowStart := targetTime.Add(-before)
+	windowEnd := targetTime.Add(after)
+	return r.find(func(t *entity.TravelTicket) bool {
+		if !isOpenCandidate(t, excludeID) || t.DepartureAt.Before(windowStart) || t.DepartureAt.After(windowEnd) {
+			return false
+		}
+		for _, s := range stops {
+			if sameEnd(end(t), s) {
+				return true
+			}
+			for _, w := range t.Waypoints {
+				if w == s {
+					return true
+				}
+			}
+		}
+		return false
+	})
+}
+
+// find returns copies of the live tickets matching keep, in ID order
+func (r *MemoryTicketRepo) find(keep func(*entity.TravelTicket) bool) []entity.TravelTicket {
+	return r.findUnscoped(func(t *entity.TravelTicket) bool { return !t.DeletedAt.Valid && keep(t) })
+}
+
+func (r *MemoryTicketRepo) findUnscoped(keep func(*entity.TravelTicket) bool) []entity.TravelTicket {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	tickets := make([]entity.TravelTicket, 0)
+	for _, t := range r.tickets {
+		if keep(&t) {
+			tickets = append(tickets, copyTicket(t))
+		}
+	}
+	sort.Slice(tickets, func(i, j int) bool { return tickets[i].ID < tickets[j].ID })
+	return tickets
+}
+
+func (r *MemoryTicketRepo) purge(drop func(*entity.TravelTicket) bool) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var n int64
+	for id, t := range r.tickets {
+		if drop(&t) {
+			delete(r.tickets, id)
+			n++
+		}
+	}
+	return n
+}
+
+func isOpenCandidate(t *entity.TravelTicket, excludeID int64) bool {
+	return t.Status == "open" && t.ID != excludeID
+}
+
+// sameEnd compares locations the way the candidate queries do: airport terminals are interchangeable
+func sameEnd(a, b string) bool {
+	return a == b || (models.IsAirportTerminal(a) && models.IsAirportTerminal(b))
+}
+
+func copyTicket(t entity.TravelTicket) entity.TravelTicket {
+	if t.Waypoints != nil {
+		t.Waypoints = append([]string(nil), t.Waypoints...)
+	}
+	if t.FlightScheduleID != nil {
+		id := *t.FlightScheduleID
+		t.FlightScheduleID = &id
+	}
+	return t
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"Travel_Sync/internal/travel/entity"
+	"context"
+	"time"
+)
+
+// TravelTicketRepository is the ticket storage the services depend on. TravelTicketRepo
+// is the Postgres implementation; MemoryTicketRepo keeps tickets in process.
+type TravelTicketRepository interface {
+	Create(ctx context.Context, ticket *entity.TravelTicket) (*entity.TravelTicket, error)
+	GetByID(ctx context.Context, id int64) (*entity.TravelTicket, error)
+	GetAll(ctx context.Context) ([]entity.TravelTicket, error)
+	Update(ctx context.Context, ticket *entity.TravelTicket) (*entity.TravelTicket, error)
+	Delete(ctx context.Context, id int64) error
+
+	GetDeletedByID(ctx context.Context, id int64) (*entity.TravelTicket, error)
+	GetDeletedByUserID(ctx context.Context, userID int64) ([]entity.TravelTicket, error)
+	GetByUserIDIncludingDeleted(ctx context.Context, userID int64) ([]entity.TravelTicket, error)
+	Restore(ctx context.Context, id int64) error
+	PurgeDeletedBefore(ctx context.Context, cutoff time.Time) (int64, error)
+	PurgeByUserIDs(ctx context.Context, userIDs []int64) (int64, error)
+
+	GetByUserID(ctx context.Context, userID int64) ([]entity.TravelTicket, error)
+	GetByFlightScheduleID(ctx context.Context, scheduleID int64) ([]entity.TravelTicket, error)
+	CountByUserID(ctx context.Context, userID int64) (int64, error)
+	ExistsForUserOnDate(ctx context.Context, userID int64, dayStart, dayEnd time.Time, direction string, excludeID *int64) (bool, error)
+
+	GetCandidatesSameDateOutbound(ctx context.Context, destination string, dayStart time.Time, excludeID int64) ([]entity.TravelTicket, error)
+	GetCandidatesSameDateReturn(ctx context.Context, source string, dayStart time.Time, excludeID int64) ([]entity.TravelTicket, error)
+	GetCandidatesTimeWindowOutbound(ctx context.Context, stops []string, targetTime time.Time, timeWindowBefore, timeWindowAfter time.Duration, excludeID int64) ([]entity.TravelTicket, error)
+	GetCandidatesTimeWindowReturn(ctx context.Context, stops []string, targetTime time.Time, timeWindowBefore, timeWindowAfter time.Duration, excludeID int64) ([]entity.TravelTicket, error)
+}
+
+var (
+	_ TravelTicketRepository = (*TravelTicketRepo)(nil)
+	_ TravelTicketRepository = (*MemoryTicketRepo)(nil)
+)
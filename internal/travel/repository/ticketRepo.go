@@ -3,6 +3,8 @@ package repository
 import (
 	"Travel_Sync/internal/travel/entity"
 	"Travel_Sync/internal/travel/models"
+	"context"
+	"encoding/json"
 	"time"
 
 	"gorm.io/gorm"
@@ -16,66 +18,132 @@ func NewTravelTicketRepo(db *gorm.DB) *TravelTicketRepo {
 	return &TravelTicketRepo{DB: db}
 }
 
-func (r *TravelTicketRepo) Create(ticket *entity.TravelTicket) (*entity.TravelTicket, error) {
-	if err := r.DB.Create(ticket).Error; err != nil {
+func (r *TravelTicketRepo) Create(ctx context.Context, ticket *entity.TravelTicket) (*entity.TravelTicket, error) {
+	if err := r.DB.WithContext(ctx).Create(ticket).Error; err != nil {
 		return nil, err
 	}
 	return ticket, nil
 }
 
-func (r *TravelTicketRepo) GetByID(id int64) (*entity.TravelTicket, error) {
+func (r *TravelTicketRepo) GetByID(ctx context.Context, id int64) (*entity.TravelTicket, error) {
 	var ticket entity.TravelTicket
-	if err := r.DB.First(&ticket, id).Error; err != nil {
+	if err := r.DB.WithContext(ctx).First(&ticket, id).Error; err != nil {
 		return nil, err
 	}
 	return &ticket, nil
 }
 
-func (r *TravelTicketRepo) GetAll() ([]entity.TravelTicket, error) {
+func (r *TravelTicketRepo) GetAll(ctx context.Context) ([]entity.TravelTicket, error) {
 	var tickets []entity.TravelTicket
-	if err := r.DB.Find(&tickets).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Find(&tickets).Error; err != nil {
 		return nil, err
 	}
 	return tickets, nil
 }
 
-func (r *TravelTicketRepo) Update(ticket *entity.TravelTicket) (*entity.TravelTicket, error) {
-	if err := r.DB.Save(ticket).Error; err != nil {
+func (r *TravelTicketRepo) Update(ctx context.Context, ticket *entity.TravelTicket) (*entity.TravelTicket, error) {
+	if err := r.DB.WithContext(ctx).Save(ticket).Error; err != nil {
 		return nil, err
 	}
 	return ticket, nil
 }
 
-func (r *TravelTicketRepo) Delete(id int64) error {
-	return r.DB.Delete(&entity.TravelTicket{ID: id}).Error
+// Delete soft-deletes the ticket; it stays restorable until purged
+func (r *TravelTicketRepo) Delete(ctx context.Context, id int64) error {
+	return r.DB.WithContext(ctx).Delete(&entity.TravelTicket{ID: id}).Error
 }
 
-func (r *TravelTicketRepo) GetByUserID(userID int64) ([]entity.TravelTicket, error) {
+// GetDeletedByID returns a soft-deleted ticket
+func (r *TravelTicketRepo) GetDeletedByID(ctx context.Context, id int64) (*entity.TravelTicket, error) {
+	var ticket entity.TravelTicket
+	if err := r.DB.WithContext(ctx).Unscoped().Where("deleted_at IS NOT NULL").First(&ticket, id).Error; err != nil {
+		return nil, err
+	}
+	return &ticket, nil
+}
+
+// GetDeletedByUserID returns the user's soft-deleted tickets, most recently deleted first
+func (r *TravelTicketRepo) GetDeletedByUserID(ctx context.Context, userID int64) ([]entity.TravelTicket, error) {
 	var tickets []entity.TravelTicket
-	if err := r.DB.Where("user_id = ?", userID).Find(&tickets).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Unscoped().
+		Where("user_id = ? AND deleted_at IS NOT NULL", userID).
+		Order("deleted_at DESC").
+		Find(&tickets).Error; err != nil {
+		return nil, err
+	}
+	return tickets, nil
+}
+
+// GetByUserIDIncludingDeleted returns all of the user's tickets, soft-deleted ones included
+func (r *TravelTicketRepo) GetByUserIDIncludingDeleted(ctx context.Context, userID int64) ([]entity.TravelTicket, error) {
+	var tickets []entity.TravelTicket
+	if err := r.DB.WithContext(ctx).Unscoped().Where("user_id = ?", userID).Order("id").Find(&tickets).Error; err != nil {
+		return nil, err
+	}
+	return tickets, nil
+}
+
+// Restore clears the soft-delete marker of a ticket
+func (r *TravelTicketRepo) Restore(ctx context.Context, id int64) error {
+	return r.DB.WithContext(ctx).Unscoped().Model(&entity.TravelTicket{}).
+		Where("id = ?", id).
+		Update("deleted_at", nil).Error
+}
+
+// PurgeDeletedBefore hard-deletes tickets soft-deleted before cutoff and returns how many were removed
+func (r *TravelTicketRepo) PurgeDeletedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	res := r.DB.WithContext(ctx).Unscoped().Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).Delete(&entity.TravelTicket{})
+	return res.RowsAffected, res.Error
+}
+
+// PurgeByUserIDs hard-deletes every ticket, deleted or not, owned by the given users
+func (r *TravelTicketRepo) PurgeByUserIDs(ctx context.Context, userIDs []int64) (int64, error) {
+	if len(userIDs) == 0 {
+		return 0, nil
+	}
+	res := r.DB.WithContext(ctx).Unscoped().Where("user_id IN ?", userIDs).Delete(&entity.TravelTicket{})
+	return res.RowsAffected, res.Error
+}
+
+func (r *TravelTicketRepo) GetByUserID(ctx context.Context, userID int64) ([]entity.TravelTicket, error) {
+	var tickets []entity.TravelTicket
+	if err := r.DB.WithContext(ctx).Where("user_id = ?", userID).Find(&tickets).Error; err != nil {
+		return nil, err
+	}
+	return tickets, nil
+}
+
+// GetByFlightScheduleID returns live tickets linked to a flight schedule entry
+func (r *TravelTicketRepo) GetByFlightScheduleID(ctx context.Context, scheduleID int64) ([]entity.TravelTicket, error) {
+	var tickets []entity.TravelTicket
+	if err := r.DB.WithContext(ctx).Where("flight_schedule_id = ?", scheduleID).Find(&tickets).Error; err != nil {
 		return nil, err
 	}
 	return tickets, nil
 }
 
 // CountByUserID returns total number of tickets created by the user
-func (r *TravelTicketRepo) CountByUserID(userID int64) (int64, error) {
+func (r *TravelTicketRepo) CountByUserID(ctx context.Context, userID int64) (int64, error) {
     var count int64
-    if err := r.DB.Model(&entity.TravelTicket{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+    if err := r.DB.WithContext(ctx).Model(&entity.TravelTicket{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
         return 0, err
     }
     return count, nil
 }
 
-// ExistsForUserOnDate checks whether a ticket exists for the given user on the same calendar date
-// defined by dayStart (00:00 UTC). Optionally excludes a ticket ID.
-// Note: dayStart should be in UTC timezone for consistent date comparisons.
-func (r *TravelTicketRepo) ExistsForUserOnDate(userID int64, dayStart time.Time, excludeID *int64) (bool, error) {
-	dayEnd := dayStart.Add(24 * time.Hour)
-	q := r.DB.Model(&entity.TravelTicket{}).Where(
+// ExistsForUserOnDate checks whether the user has a ticket in the given direction departing in
+// [dayStart, dayEnd). The bounds are the user's local midnights, so a day is not always 24h long.
+// Optionally excludes a ticket ID.
+func (r *TravelTicketRepo) ExistsForUserOnDate(ctx context.Context, userID int64, dayStart, dayEnd time.Time, direction string, excludeID *int64) (bool, error) {
+	q := r.DB.WithContext(ctx).Model(&entity.TravelTicket{}).Where(
 		"user_id = ? AND departure_at >= ? AND departure_at < ?",
-		userID, dayStart, dayEnd,
+		userID, dayStart.UTC(), dayEnd.UTC(),
 	)
+	if direction == models.DirectionReturn {
+		q = q.Where("destination IN ?", models.HostelNames())
+	} else {
+		q = q.Where("destination NOT IN ?", models.HostelNames())
+	}
 	if excludeID != nil {
 		q = q.Where("id <> ?", *excludeID)
 	}
@@ -88,10 +156,10 @@ func (r *TravelTicketRepo) ExistsForUserOnDate(userID int64, dayStart time.Time,
 
 // GetCandidatesSameDateOutbound finds tickets for outbound trips (hostel to home) on the same UTC date
 // dayStart should be in UTC timezone for consistent date comparisons.
-func (r *TravelTicketRepo) GetCandidatesSameDateOutbound(destination string, dayStart time.Time, excludeID int64) ([]entity.TravelTicket, error) {
+func (r *TravelTicketRepo) GetCandidatesSameDateOutbound(ctx context.Context, destination string, dayStart time.Time, excludeID int64) ([]entity.TravelTicket, error) {
 	var tickets []entity.TravelTicket
 	dayEnd := dayStart.Add(24 * time.Hour)
-	q := r.DB
+	q := r.DB.WithContext(ctx)
 	if models.IsAirportTerminal(destination) {
 		terminals := make([]string, 0, len(models.AirportTerminals))
 		for t := range models.AirportTerminals {
@@ -101,61 +169,72 @@ func (r *TravelTicketRepo) GetCandidatesSameDateOutbound(destination string, day
 	} else {
 		q = q.Where("destination = ?", destination)
 	}
-	err := q.Where("status = ? AND departure_at >= ? AND departure_at < ? AND id <> ?",
-		"open", dayStart, dayEnd, excludeID).Find(&tickets).Error
+	err := q.Where(openCandidate+" AND departure_at >= ? AND departure_at < ? AND id <> ?",
+		dayStart, dayEnd, excludeID).Find(&tickets).Error
 	return tickets, err
 }
 
 // GetCandidatesTimeWindowOutbound finds tickets for outbound trips (hostel to home) within a time window
 // that can span across dates. Uses timeWindowBefore and timeWindowAfter from the target ticket.
-func (r *TravelTicketRepo) GetCandidatesTimeWindowOutbound(destination string, targetTime time.Time, timeWindowBefore, timeWindowAfter time.Duration, excludeID int64) ([]entity.TravelTicket, error) {
+// stops are the target's waypoints and destination: a candidate matches when it ends at one of
+// them or passes through one of them on its way.
+func (r *TravelTicketRepo) GetCandidatesTimeWindowOutbound(ctx context.Context, stops []string, targetTime time.Time, timeWindowBefore, timeWindowAfter time.Duration, excludeID int64) ([]entity.TravelTicket, error) {
 	var tickets []entity.TravelTicket
 	windowStart := targetTime.Add(-timeWindowBefore)
 	windowEnd := targetTime.Add(timeWindowAfter)
-	
-	q := r.DB
-	if models.IsAirportTerminal(destination) {
-		terminals := make([]string, 0, len(models.AirportTerminals))
-		for t := range models.AirportTerminals {
-			terminals = append(terminals, t)
-		}
-		q = q.Where("destination IN ?", terminals)
-	} else {
-		q = q.Where("destination = ?", destination)
-	}
-	err := q.Where("status = ? AND departure_at >= ? AND departure_at <= ? AND id <> ?",
-		"open", windowStart, windowEnd, excludeID).Find(&tickets).Error
+
+	err := r.DB.WithContext(ctx).Where(r.onRoute("destination", stops)).
+		Where(openCandidate+" AND departure_at >= ? AND departure_at <= ? AND id <> ?",
+			windowStart, windowEnd, excludeID).Find(&tickets).Error
 	return tickets, err
 }
 
 // GetCandidatesTimeWindowReturn finds tickets for return trips (home to hostel) within a time window
 // that can span across dates. Uses timeWindowBefore and timeWindowAfter from the target ticket.
-func (r *TravelTicketRepo) GetCandidatesTimeWindowReturn(source string, targetTime time.Time, timeWindowBefore, timeWindowAfter time.Duration, excludeID int64) ([]entity.TravelTicket, error) {
+// stops are the target's source and waypoints: a candidate matches when it starts at one of them
+// or passes through one of them on its way.
+func (r *TravelTicketRepo) GetCandidatesTimeWindowReturn(ctx context.Context, stops []string, targetTime time.Time, timeWindowBefore, timeWindowAfter time.Duration, excludeID int64) ([]entity.TravelTicket, error) {
 	var tickets []entity.TravelTicket
 	windowStart := targetTime.Add(-timeWindowBefore)
 	windowEnd := targetTime.Add(timeWindowAfter)
-	
-	q := r.DB
-	if models.IsAirportTerminal(source) {
-		terminals := make([]string, 0, len(models.AirportTerminals))
-		for t := range models.AirportTerminals {
-			terminals = append(terminals, t)
+
+	err := r.DB.WithContext(ctx).Where(r.onRoute("source", stops)).
+		Where(openCandidate+" AND departure_at >= ? AND departure_at <= ? AND id <> ?",
+			windowStart, windowEnd, excludeID).Find(&tickets).Error
+	return tickets, err
+}
+
+// openCandidate is inlined rather than bound so that the planner can use the partial
+// indexes on open tickets (see migration 0015) with generic plans too
+const openCandidate = "status = 'open'"
+
+// onRoute matches tickets whose column (source or destination) is one of stops, or whose
+// waypoints contain one of them. Airport terminals are interchangeable for matching.
+func (r *TravelTicketRepo) onRoute(column string, stops []string) *gorm.DB {
+	ends := make([]string, 0, len(stops))
+	for _, s := range stops {
+		if models.IsAirportTerminal(s) {
+			for t := range models.AirportTerminals {
+				ends = append(ends, t)
+			}
+			continue
 		}
-		q = q.Where("source IN ?", terminals)
-	} else {
-		q = q.Where("source = ?", source)
+		ends = append(ends, s)
 	}
-	err := q.Where("status = ? AND departure_at >= ? AND departure_at <= ? AND id <> ?",
-		"open", windowStart, windowEnd, excludeID).Find(&tickets).Error
-	return tickets, err
+	cond := r.DB.Where(column+" IN ?", ends)
+	for _, s := range stops {
+		stop, _ := json.Marshal([]string{s})
+		cond = cond.Or("waypoints @> CAST(? AS jsonb)", string(stop))
+	}
+	return cond
 }
 
 // GetCandidatesSameDateReturn finds tickets for return trips (home to hostel) on the same UTC date
 // dayStart should be in UTC timezone for consistent date comparisons.
-func (r *TravelTicketRepo) GetCandidatesSameDateReturn(source string, dayStart time.Time, excludeID int64) ([]entity.TravelTicket, error) {
+func (r *TravelTicketRepo) GetCandidatesSameDateReturn(ctx context.Context, source string, dayStart time.Time, excludeID int64) ([]entity.TravelTicket, error) {
 	var tickets []entity.TravelTicket
 	dayEnd := dayStart.Add(24 * time.Hour)
-	q := r.DB
+	q := r.DB.WithContext(ctx)
 	if models.IsAirportTerminal(source) {
 		terminals := make([]string, 0, len(models.AirportTerminals))
 		for t := range models.AirportTerminals {
@@ -165,7 +244,7 @@ func (r *TravelTicketRepo) GetCandidatesSameDateReturn(source string, dayStart t
 	} else {
 		q = q.Where("source = ?", source)
 	}
-	err := q.Where("status = ? AND departure_at >= ? AND departure_at < ? AND id <> ?",
-		"open", dayStart, dayEnd, excludeID).Find(&tickets).Error
+	err := q.Where(openCandidate+" AND departure_at >= ? AND departure_at < ? AND id <> ?",
+		dayStart, dayEnd, excludeID).Find(&tickets).Error
 	return tickets, err
 }
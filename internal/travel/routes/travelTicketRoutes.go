@@ -9,7 +9,7 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func RegisterTravelRoutes(router *gin.Engine, handler *thandler.TravelTicketHandler, jwtService *secservice.JWTService) {
+func RegisterTravelRoutes(router *gin.Engine, handler *thandler.TravelTicketHandler, jwtService *secservice.JWTService, adminEmails []string) {
 	api := router.Group("/api")
 	travel := api.Group("/travel")
 	travel.Use(config.JWTMiddleware(jwtService))
@@ -17,9 +17,11 @@ func RegisterTravelRoutes(router *gin.Engine, handler *thandler.TravelTicketHand
 		travel.POST("", handler.Create)
 		travel.GET("", handler.GetAll)
 		travel.GET("/my", handler.GetMyTickets)
+		travel.GET("/deleted", handler.GetDeleted)
 		travel.GET("/:id", handler.GetByID)
 		travel.PUT("/:id", handler.Update)
 		travel.DELETE("/:id", handler.Delete)
+		travel.POST("/:id/restore", handler.Restore)
 		travel.GET("/user-responses", handler.GetUserResponses)
 
 		// Apply stricter rate limiting for recommendation endpoint
@@ -29,4 +31,11 @@ func RegisterTravelRoutes(router *gin.Engine, handler *thandler.TravelTicketHand
 			recommendations.GET("/:id/recommendations", handler.GetRecommendations)
 		}
 	}
+
+	admin := api.Group("/admin")
+	admin.Use(config.JWTMiddleware(jwtService))
+	admin.Use(config.AdminMiddleware(adminEmails))
+	{
+		admin.GET("/recommendations/cache", handler.GetCacheStats)
+	}
 }
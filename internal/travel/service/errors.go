@@ -0,0 +1,29 @@
+package service
+
+import "Travel_Sync/internal/apperr"
+
+var (
+	ErrTicketNotFound        = apperr.NotFound("ticket_not_found", "ticket not found")
+	ErrDeletedTicketNotFound = apperr.NotFound("deleted_ticket_not_found", "deleted ticket not found")
+	ErrNotTicketOwner        = apperr.Forbidden("not_ticket_owner", "forbidden")
+	ErrTicketExistsForDate   = apperr.Conflict("ticket_exists_for_date", "ticket already exists for this date")
+	ErrTicketCapReached      = apperr.Conflict("ticket_cap_reached", "Please delete your non-relevant/closed tickets to make new ones")
+
+	ErrInvalidSource         = apperr.Validation("invalid_source", "invalid source location. Please select from predefined locations")
+	ErrInvalidDestination    = apperr.Validation("invalid_destination", "invalid destination location. Please select from predefined locations")
+	ErrInvalidDeparture      = apperr.Invalid(apperr.FieldError{Field: "departure_at", Code: "departure_time", Message: "must be RFC3339 (e.g. 2025-10-01T14:30:00+05:30) or a local time (e.g. 2025-10-01T14:30)"})
+	ErrPhoneRequired         = apperr.Validation("phone_required", "phone number is required")
+	ErrGenderRequired        = apperr.Validation("gender_required", "set your gender on your profile to ask for same-gender rides")
+	ErrTooManyWaypoints      = apperr.Validation("too_many_waypoints", "too many waypoints")
+	ErrInvalidWaypoint       = apperr.Validation("invalid_waypoint", "invalid waypoint. Please select from predefined locations")
+	ErrRepeatedStop          = apperr.Validation("repeated_stop", "a route cannot visit the same location twice")
+	ErrVehicleDetails        = apperr.Validation("vehicle_details_required", "offers need vehicle details, e.g. car model and colour")
+	ErrDoesNotFitVehicle     = apperr.Validation("does_not_fit_vehicle", "party size and luggage do not fit in one vehicle")
+	ErrFlightAndTrain        = apperr.Validation("flight_and_train", "set either a flight number or a train number, not both")
+	ErrFlightNeedsHostel     = apperr.Validation("flight_needs_hostel", "with a flight number, set your hostel as source (flying out) or destination (landing)")
+	ErrFlightNotFound        = apperr.Validation("flight_not_found", "flight not found for this date")
+	ErrTrainNeedsHostel      = apperr.Validation("train_needs_hostel", "with a train number, set your hostel as source (leaving) or destination (arriving)")
+	ErrTrainNeedsStation     = apperr.Validation("train_needs_station", "with a train number the other end must be a railway station")
+	ErrTrainStationAmbiguous = apperr.Validation("train_station_ambiguous", "train stops at several Bengaluru stations, please choose one")
+	ErrTrainNotFound         = apperr.Validation("train_not_found", "train not found for this station")
+)
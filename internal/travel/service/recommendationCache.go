@@ -0,0 +1,142 @@
+package service
+
+import (
+	"Travel_Sync/internal/cache"
+	tentity "Travel_Sync/internal/travel/entity"
+	"Travel_Sync/internal/travel/models"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// RecommendationCache stores recommendation results per ticket. Each result is tagged with
+// every (stop, departure hour) its candidate query could match, and a ticket write drops
+// the tags of the stops and hour it occupies, before and after the write. Results are also
+// tagged with the ticket owner and every candidate's owner, so a profile or favourites
+// change drops the results it could affect. A result is only stored if nothing was
+// invalidated while it was computed, see Epoch. A nil *RecommendationCache disables caching.
+type RecommendationCache struct {
+	Cache cache.Cache
+	TTL   time.Duration
+}
+
+func NewRecommendationCache(c cache.Cache, ttl time.Duration) *RecommendationCache {
+	if c == nil || ttl <= 0 {
+		return nil
+	}
+	return &RecommendationCache{Cache: c, TTL: ttl}
+}
+
+func (rc *RecommendationCache) Get(ticketID int64, explain bool) (*models.RecommendationResult, bool) {
+	if rc == nil {
+		return nil, false
+	}
+	raw, ok := rc.Cache.Get(recommendationKey(ticketID, explain))
+	if !ok {
+		return nil, false
+	}
+	var result models.RecommendationResult
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, false
+	}
+	return &result, true
+}
+
+// Epoch returns the invalidation epoch to pass to Put. Read it before loading anything the
+// result is computed from.
+func (rc *RecommendationCache) Epoch() uint64 {
+	if rc == nil {
+		return 0
+	}
+	return rc.Cache.Epoch()
+}
+
+// Put stores the result for target, whose candidates were searched on stops between
+// windowStart and windowEnd and belong to userIDs. It is dropped if any invalidation
+// happened after epoch was read, as the result may have been computed from stale data.
+func (rc *RecommendationCache) Put(epoch uint64, target *tentity.TravelTicket, explain bool, stops []string, windowStart, windowEnd time.Time, userIDs []int64, result *models.RecommendationResult) {
+	if rc == nil {
+		return
+	}
+	raw, err := json.Marshal(result)
+	if err != nil {
+		return
+	}
+	tags := []string{ticketTag(target.ID), userTag(target.UserID)}
+	for _, id := range userIDs {
+		tags = append(tags, userTag(id))
+	}
+	for hour := windowStart.UTC().Truncate(time.Hour); !hour.After(windowEnd); hour = hour.Add(time.Hour) {
+		for _, stop := range stops {
+			tags = append(tags, stopTag(stop, hour))
+		}
+	}
+	rc.Cache.SetIfUnchanged(recommendationKey(target.ID, explain), raw, rc.TTL, epoch, tags...)
+}
+
+// Invalidate drops every cached result that the given ticket states could appear in,
+// including the ticket's own. Pass both the old and the new state of an updated ticket.
+func (rc *RecommendationCache) Invalidate(tickets ...*tentity.TravelTicket) {
+	if rc == nil {
+		return
+	}
+	var tags []string
+	for _, t := range tickets {
+		if t == nil {
+			continue
+		}
+		tags = append(tags, ticketTag(t.ID))
+		hour := t.DepartureAt.UTC().Truncate(time.Hour)
+		for _, stop := range models.Route(t.Source, t.Waypoints, t.Destination) {
+			tags = append(tags, stopTag(stop, hour))
+		}
+	}
+	if len(tags) > 0 {
+		rc.Cache.InvalidateTags(tags...)
+	}
+}
+
+// InvalidateUsers drops every cached result that the given users own or appear in, e.g.
+// after a profile or favourites change
+func (rc *RecommendationCache) InvalidateUsers(userIDs ...int64) {
+	if rc == nil || len(userIDs) == 0 {
+		return
+	}
+	tags := make([]string, 0, len(userIDs))
+	for _, id := range userIDs {
+		tags = append(tags, userTag(id))
+	}
+	rc.Cache.InvalidateTags(tags...)
+}
+
+// Stats reports the hit and miss counters when the backing cache keeps them
+func (rc *RecommendationCache) Stats() (cache.Stats, bool) {
+	if rc == nil {
+		return cache.Stats{}, false
+	}
+	counted, ok := rc.Cache.(interface{ Stats() cache.Stats })
+	if !ok {
+		return cache.Stats{}, false
+	}
+	return counted.Stats(), true
+}
+
+func recommendationKey(ticketID int64, explain bool) string {
+	return fmt.Sprintf("reco:%d:%t", ticketID, explain)
+}
+
+func ticketTag(ticketID int64) string {
+	return fmt.Sprintf("reco-ticket:%d", ticketID)
+}
+
+func userTag(userID int64) string {
+	return fmt.Sprintf("reco-user:%d", userID)
+}
+
+// stopTag keys a stop and departure hour; terminals share one tag as they match each other
+func stopTag(stop string, hour time.Time) string {
+	if models.IsAirportTerminal(stop) {
+		stop = "airport"
+	}
+	return fmt.Sprintf("reco-stop:%s:%d", stop, hour.Unix())
+}
@@ -1,148 +1,385 @@
 package service
 
 import (
+	"Travel_Sync/internal/apperr"
+	amodels "Travel_Sync/internal/audit/models"
+	aservice "Travel_Sync/internal/audit/service"
+	"Travel_Sync/internal/metrics"
+	sentity "Travel_Sync/internal/schedule/entity"
+	smodels "Travel_Sync/internal/schedule/models"
+	srepo "Travel_Sync/internal/schedule/repository"
+	"Travel_Sync/internal/timezone"
 	tentity "Travel_Sync/internal/travel/entity"
 	"Travel_Sync/internal/travel/mapper"
 	"Travel_Sync/internal/travel/models"
 	"Travel_Sync/internal/travel/repository"
+	uentity "Travel_Sync/internal/user/entity"
 	urepo "Travel_Sync/internal/user/repository"
+	wservice "Travel_Sync/internal/waitlist/service"
+	"context"
 	"errors"
+	"fmt"
 	"math"
 	"sort"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type TravelTicketService struct {
-	Repo     *repository.TravelTicketRepo
-	UserRepo *urepo.UserRepo
+	Repo     repository.TravelTicketRepository
+	UserRepo urepo.UserRepository
+	Audit    *aservice.AuditService
+	Flights  *srepo.FlightScheduleRepo
+	Trains   *srepo.TrainTimetableRepo
+
+	Favourites urepo.FavouriteRepository
+	Weights    models.AffinityWeights
+
+	Waitlist *wservice.WaitlistService
+
+	Recommendations *RecommendationCache
 }
 
-func NewTravelTicketService(repo *repository.TravelTicketRepo, userRepo *urepo.UserRepo) *TravelTicketService {
-	return &TravelTicketService{Repo: repo, UserRepo: userRepo}
+func NewTravelTicketService(repo repository.TravelTicketRepository, userRepo urepo.UserRepository, audit *aservice.AuditService, flights *srepo.FlightScheduleRepo, trains *srepo.TrainTimetableRepo, favourites urepo.FavouriteRepository, weights models.AffinityWeights, waitlist *wservice.WaitlistService, recommendations *RecommendationCache) *TravelTicketService {
+	return &TravelTicketService{Repo: repo, UserRepo: userRepo, Audit: audit, Flights: flights, Trains: trains, Favourites: favourites, Weights: weights, Waitlist: waitlist, Recommendations: recommendations}
 }
 
-func (s *TravelTicketService) Create(userID int64, dto *models.TravelTicketCreateDto) (*tentity.TravelTicket, error) {
+func (s *TravelTicketService) Create(ctx context.Context, userID int64, dto *models.TravelTicketCreateDto, requestID string) (*tentity.TravelTicket, error) {
+	if dto.FlightNumber != "" && dto.TrainNumber != "" {
+		return nil, ErrFlightAndTrain
+	}
+
+	// A flight or train number fills in the airport/station end and the departure time
+	var flight *sentity.FlightSchedule
+	var train *sentity.TrainTimetable
+	if dto.FlightNumber != "" {
+		sched, src, dst, dep, err := s.resolveFlight(ctx, dto.FlightNumber, dto.FlightDate, dto.Source, dto.Destination)
+		if err != nil {
+			return nil, err
+		}
+		flight = sched
+		derived := *dto
+		derived.Source, derived.Destination = src, dst
+		derived.DepartureAt = dep.Format(time.RFC3339)
+		dto = &derived
+	}
+	if dto.TrainNumber != "" {
+		stop, src, dst, dep, err := s.resolveTrain(ctx, dto.TrainNumber, dto.TrainDate, dto.Source, dto.Destination)
+		if err != nil {
+			return nil, err
+		}
+		train = stop
+		derived := *dto
+		derived.Source, derived.Destination = src, dst
+		derived.DepartureAt = dep.Format(time.RFC3339)
+		dto = &derived
+	}
+
 	// Validate source and destination locations
 	if !models.IsValidLocation(dto.Source) {
-		return nil, errors.New("invalid source location. Please select from predefined locations")
+		return nil, ErrInvalidSource
 	}
 	if !models.IsValidLocation(dto.Destination) {
-		return nil, errors.New("invalid destination location. Please select from predefined locations")
+		return nil, ErrInvalidDestination
 	}
 
 	// Enforce per-user ticket cap
-	const maxTicketsPerUser = 20
-	if count, err := s.Repo.CountByUserID(userID); err == nil {
-		if count >= maxTicketsPerUser {
-			return nil, errors.New("Please delete your non-relevant/closed tickets to make new ones")
-		}
-	} else {
+	if err := s.ensureBelowTicketCap(ctx, userID); err != nil {
 		return nil, err
 	}
 
-	user, err := s.UserRepo.GetByID(userID)
+	user, err := s.UserRepo.GetByID(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 	if user.PhoneNumber == "" && dto.PhoneNumber == "" {
-		return nil, errors.New("phone number is required")
+		return nil, ErrPhoneRequired
 	}
 
 	ticket, err := mapper.FromCreateDtoToEntity(dto, user)
 	if err != nil {
-		return nil, err
+		return nil, ErrInvalidDeparture.Wrap(err)
 	}
 	if ticket.PhoneNumber == "" {
 		ticket.PhoneNumber = user.PhoneNumber
 	}
-	// Ensure user has no other ticket on the same date (using UTC)
-	day := time.Date(ticket.DepartureAt.Year(), ticket.DepartureAt.Month(), ticket.DepartureAt.Day(), 0, 0, 0, 0, time.UTC)
-	exists, err := s.Repo.ExistsForUserOnDate(userID, day, nil)
-	if err != nil {
+	if flight != nil {
+		ticket.FlightNumber = flight.FlightNumber
+		ticket.FlightScheduleID = &flight.ID
+	}
+	if train != nil {
+		ticket.TrainNumber = train.TrainNumber
+	}
+	if err := ensureFitsVehicle(ticket); err != nil {
 		return nil, err
 	}
-	if exists {
-		return nil, errors.New("ticket already exists for this date")
+	if err := validateWaypoints(ticket); err != nil {
+		return nil, err
+	}
+	if err := validateOffer(ticket); err != nil {
+		return nil, err
+	}
+	if ticket.GenderPreference == models.GenderPrefSame && user.Gender == "" {
+		return nil, ErrGenderRequired
+	}
+	// Ensure user has no other ticket in the same direction on the same local date
+	if err := s.ensureNoTicketOnDate(ctx, user, ticket, nil); err != nil {
+		return nil, err
 	}
-	created, err := s.Repo.Create(ticket)
+	created, err := s.Repo.Create(ctx, ticket)
 	if err != nil {
 		return nil, err
 	}
+	s.Audit.Record(ctx, amodels.AuditEntry{
+		ActorID:    userID,
+		OwnerID:    userID,
+		Action:     amodels.ActionCreate,
+		EntityType: amodels.EntityTicket,
+		EntityID:   created.ID,
+		After:      created,
+		RequestID:  requestID,
+	})
+	s.Recommendations.Invalidate(created)
+	metrics.TicketsCreated.WithLabelValues(created.Source, created.Destination).Inc()
 	return created, nil
 }
 
-func (s *TravelTicketService) GetByID(id int64) (*tentity.TravelTicket, error) {
-	return s.Repo.GetByID(id)
+func (s *TravelTicketService) GetByID(ctx context.Context, id int64) (*tentity.TravelTicket, error) {
+	ticket, err := s.Repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, apperr.NotFoundAs(err, ErrTicketNotFound)
+	}
+	return ticket, nil
 }
 
-func (s *TravelTicketService) GetAll() ([]tentity.TravelTicket, error) {
-	return s.Repo.GetAll()
+// GetAll lists tickets visible to the viewer: same-gender tickets are hidden from riders of
+// another gender. With sameGenderOnly the viewer only sees tickets of riders of their gender.
+func (s *TravelTicketService) GetAll(ctx context.Context, viewerID int64, sameGenderOnly bool) ([]tentity.TravelTicket, error) {
+	tickets, err := s.Repo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	viewer, err := s.UserRepo.GetByID(ctx, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	viewerPref := models.GenderPrefAny
+	if sameGenderOnly {
+		viewerPref = models.GenderPrefSame
+	}
+	owners, err := s.usersByID(ctx, tickets)
+	if err != nil {
+		return nil, err
+	}
+
+	visible := make([]tentity.TravelTicket, 0, len(tickets))
+	for _, t := range tickets {
+		if t.UserID == viewerID {
+			visible = append(visible, t)
+			continue
+		}
+		if models.GenderCompatible(viewerPref, viewer.Gender, t.GenderPreference, owners[t.UserID].Gender) {
+			visible = append(visible, t)
+		}
+	}
+	return visible, nil
 }
 
-func (s *TravelTicketService) Update(currentUserID int64, id int64, dto *models.TravelTicketUpdateDto) (*tentity.TravelTicket, error) {
-	ticket, err := s.Repo.GetByID(id)
+func (s *TravelTicketService) Update(ctx context.Context, currentUserID int64, id int64, dto *models.TravelTicketUpdateDto, requestID string) (*tentity.TravelTicket, error) {
+	ticket, err := s.Repo.GetByID(ctx, id)
 	if err != nil {
-		return nil, err
+		return nil, apperr.NotFoundAs(err, ErrTicketNotFound)
 	}
 	if ticket.UserID != currentUserID {
-		return nil, errors.New("forbidden")
+		return nil, ErrNotTicketOwner
 	}
 
 	// Validate source and destination locations if they are being updated
 	if dto.Source != "" && !models.IsValidLocation(dto.Source) {
-		return nil, errors.New("invalid source location. Please select from predefined locations")
+		return nil, ErrInvalidSource
 	}
 	if dto.Destination != "" && !models.IsValidLocation(dto.Destination) {
-		return nil, errors.New("invalid destination location. Please select from predefined locations")
+		return nil, ErrInvalidDestination
 	}
 
-	ticket = mapper.ApplyUpdateDtoToEntity(dto, ticket)
-	// If departure time changed (or even if not), enforce single ticket per date (using UTC)
-	day := time.Date(ticket.DepartureAt.Year(), ticket.DepartureAt.Month(), ticket.DepartureAt.Day(), 0, 0, 0, 0, time.UTC)
-	excludeID := id
-	exists, err := s.Repo.ExistsForUserOnDate(currentUserID, day, &excludeID)
+	user, err := s.UserRepo.GetByID(ctx, currentUserID)
 	if err != nil {
 		return nil, err
 	}
-	if exists {
-		return nil, errors.New("ticket already exists for this date")
+
+	if dto.FlightNumber != "" && dto.TrainNumber != "" {
+		return nil, ErrFlightAndTrain
+	}
+
+	before := *ticket
+	ticket, err = mapper.ApplyUpdateDtoToEntity(dto, ticket, user)
+	if err != nil {
+		return nil, ErrInvalidDeparture.Wrap(err)
+	}
+
+	if dto.TrainNumber != "" {
+		stop, src, dst, dep, err := s.resolveTrain(ctx, dto.TrainNumber, dto.TrainDate, ticket.Source, ticket.Destination)
+		if err != nil {
+			return nil, err
+		}
+		ticket.Source, ticket.Destination, ticket.DepartureAt = src, dst, dep
+		ticket.TrainNumber = stop.TrainNumber
+		ticket.FlightNumber = ""
+		ticket.FlightScheduleID = nil
+	} else if ticket.TrainNumber != "" && (dto.Source != "" || dto.Destination != "" || dto.FlightNumber != "") {
+		// Moved to another station (or a flight) by hand: no longer on this train
+		ticket.TrainNumber = ""
+	}
+
+	if dto.FlightNumber != "" {
+		sched, src, dst, dep, err := s.resolveFlight(ctx, dto.FlightNumber, dto.FlightDate, ticket.Source, ticket.Destination)
+		if err != nil {
+			return nil, err
+		}
+		ticket.Source, ticket.Destination, ticket.DepartureAt = src, dst, dep
+		ticket.FlightNumber = sched.FlightNumber
+		ticket.FlightScheduleID = &sched.ID
+	} else if ticket.FlightScheduleID != nil && (dto.DepartureAt != "" || dto.Source != "" || dto.Destination != "") {
+		// Edited by hand: stop following the flight schedule
+		ticket.FlightNumber = ""
+		ticket.FlightScheduleID = nil
+	}
+	if err := ensureFitsVehicle(ticket); err != nil {
+		return nil, err
 	}
-	updated, err := s.Repo.Update(ticket)
+	if err := validateWaypoints(ticket); err != nil {
+		return nil, err
+	}
+	if err := validateOffer(ticket); err != nil {
+		return nil, err
+	}
+	if ticket.GenderPreference == models.GenderPrefSame && user.Gender == "" {
+		return nil, ErrGenderRequired
+	}
+	// If departure time changed (or even if not), enforce single ticket per direction per local date
+	excludeID := id
+	if err := s.ensureNoTicketOnDate(ctx, user, ticket, &excludeID); err != nil {
+		return nil, err
+	}
+	updated, err := s.Repo.Update(ctx, ticket)
 	if err != nil {
 		return nil, err
 	}
+	s.Audit.Record(ctx, amodels.AuditEntry{
+		ActorID:    currentUserID,
+		OwnerID:    updated.UserID,
+		Action:     amodels.ActionUpdate,
+		EntityType: amodels.EntityTicket,
+		EntityID:   updated.ID,
+		Before:     &before,
+		After:      updated,
+		RequestID:  requestID,
+	})
+
+	// Freed seats go to the waitlist: reopening frees every seat, otherwise only the added ones.
+	// The ticket is already saved, so the client going away must not cut this short.
+	if updated.Status == "open" {
+		if before.Status == "closed" {
+			s.Waitlist.PromoteNext(context.WithoutCancel(ctx), updated, updated.EmptySeats)
+		} else if updated.EmptySeats > before.EmptySeats {
+			s.Waitlist.PromoteNext(context.WithoutCancel(ctx), updated, updated.EmptySeats-before.EmptySeats)
+		}
+	}
+	if !updated.DepartureAt.Equal(before.DepartureAt) {
+		s.Waitlist.TicketRescheduled(context.WithoutCancel(ctx), updated)
+	}
+	s.Recommendations.Invalidate(&before, updated)
 	return updated, nil
 }
 
-func (s *TravelTicketService) Delete(currentUserID int64, id int64) error {
-	ticket, err := s.Repo.GetByID(id)
+func (s *TravelTicketService) Delete(ctx context.Context, currentUserID int64, id int64, requestID string) error {
+	ticket, err := s.Repo.GetByID(ctx, id)
 	if err != nil {
-		return err
+		return apperr.NotFoundAs(err, ErrTicketNotFound)
 	}
 	if ticket.UserID != currentUserID {
-		return errors.New("you cannot delete other user tickets")
+		return ErrNotTicketOwner.WithMessage("you cannot delete other user tickets")
+	}
+	if err := s.Repo.Delete(ctx, id); err != nil {
+		return err
 	}
-	return s.Repo.Delete(id)
+	s.Audit.Record(ctx, amodels.AuditEntry{
+		ActorID:    currentUserID,
+		OwnerID:    ticket.UserID,
+		Action:     amodels.ActionDelete,
+		EntityType: amodels.EntityTicket,
+		EntityID:   ticket.ID,
+		Before:     ticket,
+		RequestID:  requestID,
+	})
+	s.Waitlist.CancelForTicket(context.WithoutCancel(ctx), ticket)
+	s.Recommendations.Invalidate(ticket)
+	return nil
+}
+
+// GetDeleted returns the user's soft-deleted tickets that can still be restored
+func (s *TravelTicketService) GetDeleted(ctx context.Context, userID int64) ([]tentity.TravelTicket, error) {
+	return s.Repo.GetDeletedByUserID(ctx, userID)
 }
 
-func (s *TravelTicketService) GetUserResponse(id int64) (*models.TravelTicketUserResponseDto, error) {
-	ticket, err := s.Repo.GetByID(id)
+// Restore brings back a soft-deleted ticket, subject to the same cap and per-date rules as Create
+func (s *TravelTicketService) Restore(ctx context.Context, currentUserID int64, id int64, requestID string) (*tentity.TravelTicket, error) {
+	ticket, err := s.Repo.GetDeletedByID(ctx, id)
+	if err != nil {
+		return nil, apperr.NotFoundAs(err, ErrDeletedTicketNotFound)
+	}
+	if ticket.UserID != currentUserID {
+		return nil, ErrNotTicketOwner
+	}
+	if err := s.ensureBelowTicketCap(ctx, currentUserID); err != nil {
+		return nil, err
+	}
+	user, err := s.UserRepo.GetByID(ctx, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureNoTicketOnDate(ctx, user, ticket, nil); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.Restore(ctx, id); err != nil {
+		return nil, err
+	}
+	restored, err := s.Repo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	user, err := s.UserRepo.GetByID(ticket.UserID)
+	s.Audit.Record(ctx, amodels.AuditEntry{
+		ActorID:    currentUserID,
+		OwnerID:    restored.UserID,
+		Action:     amodels.ActionRestore,
+		EntityType: amodels.EntityTicket,
+		EntityID:   restored.ID,
+		After:      restored,
+		RequestID:  requestID,
+	})
+	s.Recommendations.Invalidate(restored)
+	return restored, nil
+}
+
+func (s *TravelTicketService) GetUserResponse(ctx context.Context, id int64) (*models.TravelTicketUserResponseDto, error) {
+	ticket, err := s.Repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, apperr.NotFoundAs(err, ErrTicketNotFound)
+	}
+	user, err := s.UserRepo.GetByID(ctx, ticket.UserID)
 	if err != nil {
 		return nil, err
 	}
 	return mapper.ToUserResponseDto(ticket, user), nil
 }
 
-func (s *TravelTicketService) GetUserResponses(userID int64) ([]*models.TravelTicketUserResponseDto, error) {
-	tickets, err := s.Repo.GetByUserID(userID)
+func (s *TravelTicketService) GetUserResponses(ctx context.Context, userID int64) ([]*models.TravelTicketUserResponseDto, error) {
+	tickets, err := s.Repo.GetByUserID(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
-	user, err := s.UserRepo.GetByID(userID)
+	user, err := s.UserRepo.GetByID(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -154,54 +391,116 @@ func (s *TravelTicketService) GetUserResponses(userID int64) ([]*models.TravelTi
 }
 
 // GetByUser returns all tickets created by the specified user
-func (s *TravelTicketService) GetByUser(userID int64) ([]tentity.TravelTicket, error) {
-	return s.Repo.GetByUserID(userID)
+func (s *TravelTicketService) GetByUser(ctx context.Context, userID int64) ([]tentity.TravelTicket, error) {
+	return s.Repo.GetByUserID(ctx, userID)
 }
 
-// RecommendForTicket computes best match, best group, and other alternatives
-func (s *TravelTicketService) RecommendForTicket(ticketID int64) (*models.RecommendationResult, error) {
-	t, err := s.Repo.GetByID(ticketID)
+// RecommendForTicket computes best match, best group, and other alternatives. With explain,
+// every scored ticket carries the breakdown of its score.
+func (s *TravelTicketService) RecommendForTicket(ctx context.Context, ticketID int64, explain bool) (*models.RecommendationResult, error) {
+	epoch := s.Recommendations.Epoch()
+	if cached, ok := s.Recommendations.Get(ticketID, explain); ok {
+		return cached, nil
+	}
+	t, err := s.Repo.GetByID(ctx, ticketID)
+	if err != nil {
+		return nil, apperr.NotFoundAs(err, ErrTicketNotFound)
+	}
+
+	owner, err := s.UserRepo.GetByID(ctx, t.UserID)
 	if err != nil {
 		return nil, err
 	}
+	// Dates and times in the result are shown in the ticket owner's zone
+	loc := timezone.Resolve(owner.Timezone)
 
 	// Calculate time windows for cross-date recommendations
 	beforeWindow := time.Duration(t.TimeDiffMins) * time.Minute
 	afterWindow := 60 * time.Minute
 
 	var candidates []tentity.TravelTicket
+	var stops []string
 	if models.IsHostel(t.Destination) {
 		// Return trip: Home → Hostel
-		candidates, err = s.Repo.GetCandidatesTimeWindowReturn(t.Source, t.DepartureAt, beforeWindow, afterWindow, t.ID)
+		stops = append([]string{t.Source}, t.Waypoints...)
+		candidates, err = s.Repo.GetCandidatesTimeWindowReturn(ctx, stops, t.DepartureAt, beforeWindow, afterWindow, t.ID)
 		if err != nil {
 			return nil, err
 		}
 	} else {
 		// Outbound trip: Hostel → Home
-		candidates, err = s.Repo.GetCandidatesTimeWindowOutbound(t.Destination, t.DepartureAt, beforeWindow, afterWindow, t.ID)
+		stops = append(append([]string{}, t.Waypoints...), t.Destination)
+		candidates, err = s.Repo.GetCandidatesTimeWindowOutbound(ctx, stops, t.DepartureAt, beforeWindow, afterWindow, t.ID)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	// Filter out same user tickets
+	metrics.RecommendationCandidates.WithLabelValues("fetched").Observe(float64(len(candidates)))
+
+	// Every fetched candidate's owner, as a profile change can bring a filtered one back
+	candidateUsers := make([]int64, 0, len(candidates))
+	for _, c := range candidates {
+		candidateUsers = append(candidateUsers, c.UserID)
+	}
+
+	users, err := s.usersByID(ctx, candidates)
+	if err != nil {
+		return nil, err
+	}
+	favourites, err := s.Favourites.FavouriteIDs(ctx, owner.ID)
+	if err != nil {
+		return nil, err
+	}
+	coTravellers, err := s.Waitlist.CoTravellers(ctx, owner.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Filter out same user tickets, riders who cannot share a vehicle with this ticket
+	// (conflicting vehicle types, or too many people or bags for one), and riders excluded
+	// by a same-gender preference on either ticket
+	ownLoad, _ := models.RideLoad{}.Add(t.PartySize, t.LuggageCount, t.VehicleType)
 	filteredCandidates := make([]tentity.TravelTicket, 0, len(candidates))
 	for _, c := range candidates {
-		if c.UserID != t.UserID {
-			filteredCandidates = append(filteredCandidates, c)
+		if c.UserID == t.UserID {
+			continue
+		}
+		if _, ok := ownLoad.Add(c.PartySize, c.LuggageCount, c.VehicleType); !ok {
+			continue
+		}
+		if !models.GenderCompatible(t.GenderPreference, owner.Gender, c.GenderPreference, users[c.UserID].Gender) {
+			continue
 		}
+		if !models.TypesCompatible(t.Type, c.Type) {
+			continue
+		}
+		filteredCandidates = append(filteredCandidates, c)
 	}
 	candidates = filteredCandidates
+	metrics.RecommendationCandidates.WithLabelValues("scored").Observe(float64(len(candidates)))
 
-	// Score all candidates (time window filtering is now handled by repository)
+	// Score all candidates (time window filtering is now handled by repository). Stop early
+	// once the client has gone or the request deadline has passed.
+	scoringStart := time.Now()
 	scored := make([]models.ScoredTicket, 0, len(candidates))
-	for _, c := range candidates {
+	for i, c := range candidates {
+		if i%scoringCancelCheckEvery == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
 
-		score := s.scoreTicket(*t, c)
-		// fetch minimal user details for candidate
-		cu, uerr := s.UserRepo.GetByID(c.UserID)
+		social := socialSignals{
+			sameBatch:   owner.Batch != "" && owner.Batch == users[c.UserID].Batch,
+			favourite:   favourites[c.UserID],
+			coTraveller: coTravellers[c.UserID],
+		}
+		breakdown := s.scoreTicket(*t, c, social)
+		score := breakdown.Score
+		// minimal user details for candidate
 		var minUser models.MinimalUser
-		if uerr == nil && cu != nil {
+		if cu, ok := users[c.UserID]; ok {
 			minUser = models.MinimalUser{Name: cu.Name, Batch: cu.Batch, Email: cu.Email}
 		}
 		public := models.PublicTicket{
@@ -212,33 +511,83 @@ func (s *TravelTicketService) RecommendForTicket(ticketID int64) (*models.Recomm
 			TimeDiffMins: c.TimeDiffMins,
 			PhoneNumber:  c.PhoneNumber,
 			Status:       c.Status,
+			LuggageCount: c.LuggageCount,
+			VehicleType:  c.VehicleType,
+			PartySize:    c.PartySize,
 			CreatedAt:    c.CreatedAt,
 			UpdatedAt:    c.UpdatedAt,
+
+			GenderPreference: c.GenderPreference,
+			Waypoints:        c.Waypoints,
+			Type:             c.Type,
+			VehicleDetails:   c.VehicleDetails,
+			PricePerSeat:     c.PricePerSeat,
 		}
-		scored = append(scored, models.ScoredTicket{
+		sct := models.ScoredTicket{
 			Ticket:      public,
 			Score:       score,
-			Date:        c.DepartureAt.Format("2006-01-02"),
-			Time:        c.DepartureAt.Format("15:04"),
+			Date:        c.DepartureAt.In(loc).Format("2006-01-02"),
+			Time:        c.DepartureAt.In(loc).Format("15:04"),
 			User:        minUser,
 			CandidateID: c.ID,
-		})
+			Affinity:    breakdown.Affinity,
+		}
+		if explain {
+			sct.Explain = &breakdown
+		}
+		scored = append(scored, sct)
 	}
 
-	sort.Slice(scored, func(i, j int) bool { return scored[i].Score > scored[j].Score })
+	// Equal scores go to the candidate with the stronger social ties
+	sort.SliceStable(scored, func(i, j int) bool {
+		if scored[i].Score != scored[j].Score {
+			return scored[i].Score > scored[j].Score
+		}
+		return scored[i].Affinity > scored[j].Affinity
+	})
+	metrics.RecommendationScoringDuration.Observe(time.Since(scoringStart).Seconds())
+
+	result := &models.RecommendationResult{Timezone: loc.String(), AvailableRides: []models.ScoredTicket{}}
+
+	// Seats offered by others are listed on their own; the sections below are co-riders
+	if t.Type != models.TicketTypeOffer {
+		riders := make([]models.ScoredTicket, 0, len(scored))
+		for _, sct := range scored {
+			if sct.Ticket.Type == models.TicketTypeOffer {
+				result.AvailableRides = append(result.AvailableRides, sct)
+			} else {
+				riders = append(riders, sct)
+			}
+		}
+		scored = riders
+	}
 
-	result := &models.RecommendationResult{}
 	if len(scored) > 0 {
 		result.BestMatch = &scored[0]
 	}
 
-	// Build Best Group: greedy selection from time-window filtered candidates
+	// Build Best Group: greedy selection from time-window filtered candidates, taking the
+	// next best candidate whose riders and luggage still fit in one vehicle with the group.
+	// An offer's group is further limited to the seats it offers.
 	group := make([]models.ScoredTicket, 0, 4)
+	load := ownLoad
+	seatsLeft := t.EmptySeats
 	for _, sct := range scored {
 		if len(group) >= 4 {
 			break
 		}
-		// All candidates are already within the time window, so add them to group
+		next, ok := load.Add(sct.Ticket.PartySize, sct.Ticket.LuggageCount, sct.Ticket.VehicleType)
+		if !ok {
+			continue
+		}
+		if t.Type == models.TicketTypeOffer {
+			seats := max(sct.Ticket.PartySize, 1)
+			if seats > seatsLeft {
+				continue
+			}
+			seatsLeft -= seats
+		}
+		load = next
 		group = append(group, sct)
 	}
 
@@ -248,6 +597,7 @@ func (s *TravelTicketService) RecommendForTicket(ticketID int64) (*models.Recomm
 	// If group size is less than 2, add those tickets to other alternatives
 	if len(group) >= 2 {
 		result.BestGroup = group
+		result.GroupVehicle = load.SmallestVehicle()
 	} else {
 		// Only add to others if not BestMatch
 		for _, sct := range group {
@@ -275,59 +625,307 @@ func (s *TravelTicketService) RecommendForTicket(ticketID int64) (*models.Recomm
 	}
 	result.OtherAlternatives = others
 
+	s.Recommendations.Put(epoch, t, explain, stops, t.DepartureAt.Add(-beforeWindow), t.DepartureAt.Add(afterWindow), candidateUsers, result)
 	return result, nil
 }
 
-// helper scoring and filters
-func (s *TravelTicketService) scoreTicket(target, candidate tentity.TravelTicket) float64 {
-	score := 100.0
+const maxTicketsPerUser = 20
+
+// scoringCancelCheckEvery is how many candidates are scored between context checks
+const scoringCancelCheckEvery = 32
+
+// usersByID loads the owners of the given tickets in one query
+func (s *TravelTicketService) usersByID(ctx context.Context, tickets []tentity.TravelTicket) (map[int64]uentity.User, error) {
+	ids := make([]int64, 0, len(tickets))
+	seen := make(map[int64]bool, len(tickets))
+	for _, t := range tickets {
+		if !seen[t.UserID] {
+			seen[t.UserID] = true
+			ids = append(ids, t.UserID)
+		}
+	}
+	users, err := s.UserRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int64]uentity.User, len(users))
+	for _, u := range users {
+		byID[u.ID] = u
+	}
+	return byID, nil
+}
+
+// Detour pricing for multi-stop matches
+const (
+	detourPenaltyPerKm = 2.0
+	maxDetourKm        = 12.0
+)
+
+// sameTrainBonus is added to the score of candidates travelling on the same train
+const sameTrainBonus = 30.0
+
+// resolveFlight looks up a flight and derives the ticket's route and departure from it. The
+// hostel end given by the user decides the direction: a hostel source means the user is flying
+// out, a hostel destination means the user is landing.
+func (s *TravelTicketService) resolveFlight(ctx context.Context, flightNumber, flightDate, source, destination string) (*sentity.FlightSchedule, string, string, time.Time, error) {
+	var direction string
+	switch {
+	case models.IsHostel(destination):
+		direction = models.DirectionReturn
+	case models.IsHostel(source):
+		direction = models.DirectionOutbound
+	default:
+		return nil, "", "", time.Time{}, ErrFlightNeedsHostel
+	}
+
+	sched, err := s.Flights.GetByNumberAndDate(ctx, smodels.NormalizeFlightNumber(flightNumber), flightDate)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", "", time.Time{}, ErrFlightNotFound
+		}
+		return nil, "", "", time.Time{}, err
+	}
+	terminal, departureAt, err := smodels.FlightTicketLeg(sched, direction)
+	if err != nil {
+		return nil, "", "", time.Time{}, err
+	}
+	if direction == models.DirectionReturn {
+		return sched, terminal, destination, departureAt, nil
+	}
+	return sched, source, terminal, departureAt, nil
+}
+
+// ensureBelowTicketCap rejects users who already own maxTicketsPerUser live tickets
+func (s *TravelTicketService) ensureBelowTicketCap(ctx context.Context, userID int64) error {
+	count, err := s.Repo.CountByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if count >= maxTicketsPerUser {
+		return ErrTicketCapReached
+	}
+	return nil
+}
+
+// validateWaypoints checks that intermediate stops are known locations that actually add a stop
+func validateWaypoints(ticket *tentity.TravelTicket) error {
+	if len(ticket.Waypoints) > models.MaxWaypoints {
+		return ErrTooManyWaypoints.WithMessage(fmt.Sprintf("at most %d waypoints are allowed", models.MaxWaypoints))
+	}
+	route := models.Route(ticket.Source, ticket.Waypoints, ticket.Destination)
+	seen := make(map[string]bool, len(route))
+	for _, stop := range route {
+		if !models.IsValidLocation(stop) {
+			return ErrInvalidWaypoint
+		}
+		if seen[stop] {
+			return ErrRepeatedStop
+		}
+		seen[stop] = true
+	}
+	return nil
+}
+
+// validateOffer keeps offer-only details off other ticket types and requires them on offers
+func validateOffer(ticket *tentity.TravelTicket) error {
+	if ticket.Type != models.TicketTypeOffer {
+		ticket.VehicleDetails = ""
+		ticket.PricePerSeat = 0
+		return nil
+	}
+	if ticket.VehicleDetails == "" {
+		return ErrVehicleDetails
+	}
+	return nil
+}
+
+// ensureFitsVehicle rejects tickets whose own party and luggage do not fit the preferred vehicle
+// (or the largest one when there is no preference)
+func ensureFitsVehicle(ticket *tentity.TravelTicket) error {
+	if _, ok := (models.RideLoad{}).Add(ticket.PartySize, ticket.LuggageCount, ticket.VehicleType); !ok {
+		if ticket.VehicleType != "" {
+			return ErrDoesNotFitVehicle.WithMessage("party size and luggage do not fit in a " + ticket.VehicleType)
+		}
+		return ErrDoesNotFitVehicle
+	}
+	return nil
+}
+
+// ensureNoTicketOnDate enforces one ticket per user, per direction, per calendar date.
+// The date is taken in the user's zone (campus zone by default), so an outbound and a
+// return ticket on the same day are both allowed.
+func (s *TravelTicketService) ensureNoTicketOnDate(ctx context.Context, user *uentity.User, ticket *tentity.TravelTicket, excludeID *int64) error {
+	loc := timezone.Resolve(user.Timezone)
+	local := ticket.DepartureAt.In(loc)
+	dayStart := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	dayEnd := dayStart.AddDate(0, 0, 1)
+	direction := models.TripDirection(ticket.Source, ticket.Destination)
+	exists, err := s.Repo.ExistsForUserOnDate(ctx, user.ID, dayStart, dayEnd, direction, excludeID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrTicketExistsForDate
+	}
+	return nil
+}
+
+// resolveTrain looks up a train stop and derives the ticket's route and departure from it. As with
+// flights, the hostel end decides the direction. The station end may be left empty when the train
+// stops at only one Bengaluru station.
+func (s *TravelTicketService) resolveTrain(ctx context.Context, trainNumber, trainDate, source, destination string) (*sentity.TrainTimetable, string, string, time.Time, error) {
+	var direction, station string
+	switch {
+	case models.IsHostel(destination):
+		direction, station = models.DirectionReturn, source
+	case models.IsHostel(source):
+		direction, station = models.DirectionOutbound, destination
+	default:
+		return nil, "", "", time.Time{}, ErrTrainNeedsHostel
+	}
+	if station != "" && !models.IsRailwayStation(station) {
+		return nil, "", "", time.Time{}, ErrTrainNeedsStation
+	}
+
+	stops, err := s.Trains.GetByNumber(ctx, smodels.NormalizeTrainNumber(trainNumber))
+	if err != nil {
+		return nil, "", "", time.Time{}, err
+	}
+	var stop *sentity.TrainTimetable
+	for i := range stops {
+		if station == "" || stops[i].Station == station {
+			if stop != nil {
+				return nil, "", "", time.Time{}, ErrTrainStationAmbiguous
+			}
+			stop = &stops[i]
+		}
+	}
+	if stop == nil {
+		return nil, "", "", time.Time{}, ErrTrainNotFound
+	}
+
+	departureAt, err := smodels.TrainTicketLeg(stop, trainDate, direction)
+	if err != nil {
+		return nil, "", "", time.Time{}, err
+	}
+	if direction == models.DirectionReturn {
+		return stop, stop.Station, destination, departureAt, nil
+	}
+	return stop, source, stop.Station, departureAt, nil
+}
+
+// socialSignals describe how the target ticket's owner relates to a candidate's owner
+type socialSignals struct {
+	sameBatch   bool
+	favourite   bool
+	coTraveller bool // shared a departed trip through a waitlist promotion
+}
+
+// helper scoring and filters. Every step that changes the score is recorded in the breakdown.
+func (s *TravelTicketService) scoreTicket(target, candidate tentity.TravelTicket, social socialSignals) models.ScoreBreakdown {
+	b := models.ScoreBreakdown{Base: 100, Factors: []models.ScoreFactor{}}
+	score := b.Base
+	apply := func(name, effect string, value float64, detail string) {
+		switch effect {
+		case "subtract":
+			score -= value
+		case "multiply":
+			score *= value
+		case "add":
+			score += value
+		}
+		b.Factors = append(b.Factors, models.ScoreFactor{Name: name, Effect: effect, Value: value, Detail: detail})
+	}
 
 	// Time difference penalty
 	diffMins := math.Abs(candidate.DepartureAt.Sub(target.DepartureAt).Minutes())
-	score -= 0.5 * diffMins
+	if diffMins > 0 {
+		apply("time_difference", "subtract", 0.5*diffMins, fmt.Sprintf("%.0f min apart, 0.5 per minute", diffMins))
+	}
 	if score < 0 {
 		score = 0
 	}
 
-	// Source / destination weighting
-	if models.IsHostel(target.Destination) {
+	// Source / destination weighting. For multi-stop tickets one rider's trip has to lie along
+	// the other's route instead, and every extra kilometre the car drives to serve both costs points
+	if len(target.Waypoints) > 0 || len(candidate.Waypoints) > 0 {
+		detour, ok := models.SharedRouteDetourKm(
+			models.Route(target.Source, target.Waypoints, target.Destination),
+			models.Route(candidate.Source, candidate.Waypoints, candidate.Destination),
+		)
+		if !ok || detour > maxDetourKm {
+			apply("detour", "multiply", 0, "routes do not overlap within the detour limit")
+		} else if detour > 0 {
+			apply("detour", "subtract", detourPenaltyPerKm*detour, fmt.Sprintf("%.1f km extra", detour))
+		}
+	} else if models.IsHostel(target.Destination) {
 		// Return: Home → Hostel
 		if target.Source != candidate.Source {
 			if models.IsAirportTerminal(target.Source) && models.IsAirportTerminal(candidate.Source) && models.AreNearbyTerminals(target.Source, candidate.Source) {
-				score *= 0.8 // nearby terminal less penalty
+				apply("source", "multiply", 0.8, "nearby terminal") // nearby terminal less penalty
 			} else {
-				score *= 0.0 // different source, no match
+				apply("source", "multiply", 0.0, "different pickup") // different source, no match
 			}
 		}
 		if target.Destination != candidate.Destination {
 			if models.AreNearbyHostels(target.Destination, candidate.Destination) {
-				score *= 0.7 // nearby hostel
+				apply("destination", "multiply", 0.7, "nearby hostel")
 			} else {
-				score -= 20 // different hostel
+				apply("destination", "subtract", 20, "different hostel")
 			}
 		}
 	} else {
 		// Outbound: Hostel → Home
 		if target.Source != candidate.Source {
 			if models.AreNearbyHostels(target.Source, candidate.Source) {
-				score *= 0.85 // nearby hostel less priority
+				apply("source", "multiply", 0.85, "nearby hostel") // nearby hostel less priority
 			} else {
-				score *= 0.0 // different hostel, invalid
+				apply("source", "multiply", 0.0, "different hostel") // different hostel, invalid
 			}
 		}
 		if target.Destination != candidate.Destination {
 			if models.AreNearbyTerminals(target.Destination, candidate.Destination) {
-				score *= 0.6 // nearby terminal less priority - more penalty
+				apply("destination", "multiply", 0.6, "nearby terminal") // nearby terminal less priority - more penalty
 			} else {
-				score -= 20
+				apply("destination", "subtract", 20, "different drop-off")
 			}
 		}
 	}
 
+	// Bonuses are added on top of viable matches, uncapped, so they also separate otherwise
+	// equal ones; they never rescue a different route. Their sum is kept as affinity, which
+	// breaks ties between candidates that score the same, zero included.
+	type bonus struct {
+		name, detail string
+		value        float64
+	}
+	var bonuses []bonus
+	// Riders on the same train are very likely to want the same cab
+	if target.TrainNumber != "" && target.TrainNumber == candidate.TrainNumber {
+		bonuses = append(bonuses, bonus{"same_train", "train " + target.TrainNumber, sameTrainBonus})
+	}
+	if social.sameBatch && s.Weights.SameBatch > 0 {
+		bonuses = append(bonuses, bonus{"same_batch", "", s.Weights.SameBatch})
+	}
+	if social.favourite && s.Weights.Favourite > 0 {
+		bonuses = append(bonuses, bonus{"favourite", "", s.Weights.Favourite})
+	}
+	if social.coTraveller && s.Weights.CoTraveller > 0 {
+		bonuses = append(bonuses, bonus{"co_traveller", "travelled together before", s.Weights.CoTraveller})
+	}
+	viable := score > 0
+	for _, bn := range bonuses {
+		b.Affinity += bn.value
+		if viable {
+			apply(bn.name, "add", bn.value, bn.detail)
+		}
+	}
+
 	if score < 0 {
 		score = 0
 	}
-	return score
+	b.Score = score
+	return b
 }
 
 func absDuration(d time.Duration) time.Duration {
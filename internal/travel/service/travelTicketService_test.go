@@ -0,0 +1,488 @@
+package service
+
+import (
+	"Travel_Sync/internal/apperr"
+	"Travel_Sync/internal/timezone"
+	tentity "Travel_Sync/internal/travel/entity"
+	"Travel_Sync/internal/travel/models"
+	"Travel_Sync/internal/travel/repository"
+	uentity "Travel_Sync/internal/user/entity"
+	urepo "Travel_Sync/internal/user/repository"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+const (
+	hostel1   = "Uniworld-1"
+	hostel2   = "Uniworld-2"
+	terminal1 = "Kempegowda International Airport Terminal-1"
+	terminal2 = "Kempegowda International Airport Terminal-2"
+	smvt      = "SMVT Bengaluru Railway station"
+)
+
+var testWeights = models.AffinityWeights{SameBatch: 5, Favourite: 15, CoTraveller: 10}
+
+// departure is a fixed instant in the future, so tickets are never in the past
+var departure = time.Date(2030, 10, 1, 9, 0, 0, 0, time.UTC)
+
+type fixture struct {
+	svc        *TravelTicketService
+	tickets    *repository.MemoryTicketRepo
+	users      *urepo.MemoryUserRepo
+	favourites *urepo.MemoryFavouriteRepo
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+	if err := timezone.SetDefault("Asia/Kolkata"); err != nil {
+		t.Fatal(err)
+	}
+	favourites := urepo.NewMemoryFavouriteRepo()
+	f := &fixture{
+		tickets:    repository.NewMemoryTicketRepo(),
+		users:      urepo.NewMemoryUserRepo(favourites),
+		favourites: favourites,
+	}
+	f.svc = NewTravelTicketService(f.tickets, f.users, nil, nil, nil, favourites, testWeights, nil, nil)
+	return f
+}
+
+func (f *fixture) user(t *testing.T, u uentity.User) *uentity.User {
+	t.Helper()
+	created, err := f.users.Create(context.Background(), &u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return created
+}
+
+func (f *fixture) ticket(t *testing.T, tk tentity.TravelTicket) *tentity.TravelTicket {
+	t.Helper()
+	if tk.PhoneNumber == "" {
+		tk.PhoneNumber = "9876543210"
+	}
+	if tk.EmptySeats == 0 {
+		tk.EmptySeats = 2
+	}
+	created, err := f.tickets.Create(context.Background(), &tk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return created
+}
+
+func TestCreateLimits(t *testing.T) {
+	outbound := func(at string) *models.TravelTicketCreateDto {
+		return &models.TravelTicketCreateDto{Source: hostel1, Destination: terminal1, DepartureAt: at, TimeDiffMins: 30, EmptySeats: 2, PhoneNumber: "9876543210"}
+	}
+	inbound := func(at string) *models.TravelTicketCreateDto {
+		return &models.TravelTicketCreateDto{Source: terminal1, Destination: hostel1, DepartureAt: at, TimeDiffMins: 30, EmptySeats: 2, PhoneNumber: "9876543210"}
+	}
+
+	tests := []struct {
+		name     string
+		user     uentity.User
+		existing []tentity.TravelTicket // owned by the user
+		dto      *models.TravelTicketCreateDto
+		wantErr  *apperr.Error
+	}{
+		{
+			name: "first ticket",
+			dto:  outbound("2030-10-01T09:00:00Z"),
+		},
+		{
+			name:     "below the cap",
+			existing: repeatTickets(maxTicketsPerUser-1, departure.AddDate(0, 1, 0)),
+			dto:      outbound("2030-10-01T09:00:00Z"),
+		},
+		{
+			name:     "cap reached",
+			existing: repeatTickets(maxTicketsPerUser, departure.AddDate(0, 1, 0)),
+			dto:      outbound("2030-10-01T09:00:00Z"),
+			wantErr:  ErrTicketCapReached,
+		},
+		{
+			name:     "same direction on the same date",
+			existing: []tentity.TravelTicket{{Source: hostel1, Destination: smvt, DepartureAt: departure.Add(5 * time.Hour)}},
+			dto:      outbound("2030-10-01T09:00:00Z"),
+			wantErr:  ErrTicketExistsForDate,
+		},
+		{
+			name:     "other direction on the same date",
+			existing: []tentity.TravelTicket{{Source: hostel1, Destination: terminal1, DepartureAt: departure}},
+			dto:      inbound("2030-10-01T15:00:00Z"),
+		},
+		{
+			name:     "same direction on the next date",
+			existing: []tentity.TravelTicket{{Source: hostel1, Destination: terminal1, DepartureAt: departure}},
+			dto:      outbound("2030-10-02T09:00:00Z"),
+		},
+		{
+			// 20:00 UTC on the 1st is already the 2nd in Kolkata
+			name:     "date is taken in the user's zone",
+			user:     uentity.User{Timezone: "Asia/Kolkata"},
+			existing: []tentity.TravelTicket{{Source: hostel1, Destination: terminal1, DepartureAt: time.Date(2030, 10, 1, 20, 0, 0, 0, time.UTC)}},
+			dto:      outbound("2030-10-02T10:00"),
+			wantErr:  ErrTicketExistsForDate,
+		},
+		{
+			name:     "same UTC date, different local dates",
+			user:     uentity.User{Timezone: "Asia/Kolkata"},
+			existing: []tentity.TravelTicket{{Source: hostel1, Destination: terminal1, DepartureAt: time.Date(2030, 10, 1, 20, 0, 0, 0, time.UTC)}},
+			dto:      outbound("2030-10-01T10:00"),
+		},
+		{
+			name:    "unknown source",
+			dto:     &models.TravelTicketCreateDto{Source: "Somewhere", Destination: terminal1, DepartureAt: "2030-10-01T09:00:00Z", EmptySeats: 1, PhoneNumber: "9876543210"},
+			wantErr: ErrInvalidSource,
+		},
+		{
+			name:    "same-gender ride without a gender",
+			dto:     &models.TravelTicketCreateDto{Source: hostel1, Destination: terminal1, DepartureAt: "2030-10-01T09:00:00Z", EmptySeats: 1, PhoneNumber: "9876543210", GenderPreference: models.GenderPrefSame},
+			wantErr: ErrGenderRequired,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+			u := tt.user
+			u.Email, u.Batch = "rider@example.com", "Batch2025"
+			owner := f.user(t, u)
+			for _, tk := range tt.existing {
+				tk.UserID = owner.ID
+				f.ticket(t, tk)
+			}
+
+			created, err := f.svc.Create(context.Background(), owner.ID, tt.dto, "")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Create() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			if created.UserID != owner.ID || created.Status != "open" {
+				t.Errorf("Create() = user %d status %q, want user %d status open", created.UserID, created.Status, owner.ID)
+			}
+		})
+	}
+}
+
+func TestScoreTicket(t *testing.T) {
+	target := tentity.TravelTicket{Source: hostel1, Destination: terminal1, DepartureAt: departure}
+	inboundTarget := tentity.TravelTicket{Source: terminal1, Destination: hostel1, DepartureAt: departure}
+	like := func(base tentity.TravelTicket, change func(*tentity.TravelTicket)) tentity.TravelTicket {
+		change(&base)
+		return base
+	}
+
+	tests := []struct {
+		name         string
+		target       tentity.TravelTicket
+		candidate    tentity.TravelTicket
+		social       socialSignals
+		wantScore    float64
+		wantAffinity float64
+	}{
+		{
+			name:      "same route and time",
+			target:    target,
+			candidate: target,
+			wantScore: 100,
+		},
+		{
+			name:      "half a point per minute apart",
+			target:    target,
+			candidate: like(target, func(c *tentity.TravelTicket) { c.DepartureAt = departure.Add(30 * time.Minute) }),
+			wantScore: 85,
+		},
+		{
+			name:      "time penalty floors at zero",
+			target:    target,
+			candidate: like(target, func(c *tentity.TravelTicket) { c.DepartureAt = departure.Add(5 * time.Hour) }),
+			wantScore: 0,
+		},
+		{
+			name:      "nearby hostel",
+			target:    target,
+			candidate: like(target, func(c *tentity.TravelTicket) { c.Source = hostel2 }),
+			wantScore: 85,
+		},
+		{
+			name:      "nearby terminal",
+			target:    target,
+			candidate: like(target, func(c *tentity.TravelTicket) { c.Destination = terminal2 }),
+			wantScore: 60,
+		},
+		{
+			name:      "different drop-off",
+			target:    target,
+			candidate: like(target, func(c *tentity.TravelTicket) { c.Destination = smvt }),
+			wantScore: 80,
+		},
+		{
+			name:      "return trip from a different pickup",
+			target:    inboundTarget,
+			candidate: like(inboundTarget, func(c *tentity.TravelTicket) { c.Source = smvt }),
+			wantScore: 0,
+		},
+		{
+			name:      "return trip to a nearby hostel",
+			target:    inboundTarget,
+			candidate: like(inboundTarget, func(c *tentity.TravelTicket) { c.Destination = hostel2 }),
+			wantScore: 70,
+		},
+		{
+			name:         "bonuses are not capped at 100",
+			target:       target,
+			candidate:    target,
+			social:       socialSignals{sameBatch: true, favourite: true},
+			wantScore:    120,
+			wantAffinity: 20,
+		},
+		{
+			name:         "co-traveller",
+			target:       target,
+			candidate:    like(target, func(c *tentity.TravelTicket) { c.DepartureAt = departure.Add(-20 * time.Minute) }),
+			social:       socialSignals{coTraveller: true},
+			wantScore:    100,
+			wantAffinity: 10,
+		},
+		{
+			name:         "same train",
+			target:       like(target, func(c *tentity.TravelTicket) { c.TrainNumber = "12627" }),
+			candidate:    like(target, func(c *tentity.TravelTicket) { c.TrainNumber = "12627" }),
+			wantScore:    100 + sameTrainBonus,
+			wantAffinity: sameTrainBonus,
+		},
+		{
+			name:         "bonuses do not rescue a different route",
+			target:       inboundTarget,
+			candidate:    like(inboundTarget, func(c *tentity.TravelTicket) { c.Source = smvt }),
+			social:       socialSignals{favourite: true},
+			wantScore:    0,
+			wantAffinity: 15,
+		},
+	}
+	svc := &TravelTicketService{Weights: testWeights}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := svc.scoreTicket(tt.target, tt.candidate, tt.social)
+			if b.Score != tt.wantScore || b.Affinity != tt.wantAffinity {
+				t.Errorf("scoreTicket() = score %v affinity %v, want %v and %v (factors %+v)", b.Score, b.Affinity, tt.wantScore, tt.wantAffinity, b.Factors)
+			}
+		})
+	}
+}
+
+func TestRecommendForTicketFilters(t *testing.T) {
+	tests := []struct {
+		name      string
+		target    tentity.TravelTicket
+		owner     uentity.User
+		candidate tentity.TravelTicket
+		rider     uentity.User
+		favourite bool
+		// where the candidate should end up: "riders", "rides" (available_rides) or "" (filtered out)
+		want string
+	}{
+		{
+			name:      "matching sharer",
+			candidate: tentity.TravelTicket{},
+			want:      "riders",
+		},
+		{
+			name:      "outside the time window",
+			candidate: tentity.TravelTicket{DepartureAt: departure.Add(3 * time.Hour)},
+		},
+		{
+			name:      "closed ticket",
+			candidate: tentity.TravelTicket{Status: "closed"},
+		},
+		{
+			name:      "conflicting vehicle types",
+			target:    tentity.TravelTicket{VehicleType: models.VehicleSedan},
+			candidate: tentity.TravelTicket{VehicleType: models.VehicleAuto},
+		},
+		{
+			name:      "too many riders for one vehicle",
+			target:    tentity.TravelTicket{PartySize: 3},
+			candidate: tentity.TravelTicket{PartySize: 4},
+		},
+		{
+			name:      "too much luggage for one vehicle",
+			target:    tentity.TravelTicket{LuggageCount: 3},
+			candidate: tentity.TravelTicket{LuggageCount: 3},
+		},
+		{
+			name:      "same-gender target, other gender",
+			target:    tentity.TravelTicket{GenderPreference: models.GenderPrefSame},
+			owner:     uentity.User{Gender: "female"},
+			candidate: tentity.TravelTicket{},
+			rider:     uentity.User{Gender: "male"},
+		},
+		{
+			name:      "same-gender candidate, same gender",
+			owner:     uentity.User{Gender: "female"},
+			candidate: tentity.TravelTicket{GenderPreference: models.GenderPrefSame},
+			rider:     uentity.User{Gender: "female"},
+			want:      "riders",
+		},
+		{
+			name:      "same-gender candidate, owner without gender",
+			candidate: tentity.TravelTicket{GenderPreference: models.GenderPrefSame},
+			rider:     uentity.User{Gender: "female"},
+		},
+		{
+			name:      "request does not pair with a sharer",
+			candidate: tentity.TravelTicket{Type: models.TicketTypeRequest},
+		},
+		{
+			name:      "offer is listed as an available ride",
+			candidate: tentity.TravelTicket{Type: models.TicketTypeOffer, VehicleDetails: "white sedan"},
+			want:      "rides",
+		},
+		{
+			name:      "offer lists a request as a rider",
+			target:    tentity.TravelTicket{Type: models.TicketTypeOffer, VehicleDetails: "white sedan"},
+			candidate: tentity.TravelTicket{Type: models.TicketTypeRequest},
+			want:      "riders",
+		},
+		{
+			name:      "two offers do not pair",
+			target:    tentity.TravelTicket{Type: models.TicketTypeOffer, VehicleDetails: "white sedan"},
+			candidate: tentity.TravelTicket{Type: models.TicketTypeOffer, VehicleDetails: "red suv"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+			owner := tt.owner
+			owner.Email, owner.Batch = "owner@example.com", "Batch2025"
+			ownerID := f.user(t, owner).ID
+			rider := tt.rider
+			rider.Email, rider.Batch = "rider@example.com", "Batch2024"
+			riderID := f.user(t, rider).ID
+
+			target := withRoute(tt.target, ownerID)
+			candidate := withRoute(tt.candidate, riderID)
+			targetID := f.ticket(t, target).ID
+			candidateID := f.ticket(t, candidate).ID
+
+			result, err := f.svc.RecommendForTicket(context.Background(), targetID, false)
+			if err != nil {
+				t.Fatalf("RecommendForTicket() error = %v", err)
+			}
+			if got := section(result, candidateID); got != tt.want {
+				t.Errorf("candidate listed in %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecommendForTicketSkipsOwnTickets(t *testing.T) {
+	f := newFixture(t)
+	owner := f.user(t, uentity.User{Email: "owner@example.com", Batch: "Batch2025"})
+	targetID := f.ticket(t, withRoute(tentity.TravelTicket{}, owner.ID)).ID
+	ownID := f.ticket(t, withRoute(tentity.TravelTicket{DepartureAt: departure.Add(10 * time.Minute)}, owner.ID)).ID
+
+	result, err := f.svc.RecommendForTicket(context.Background(), targetID, false)
+	if err != nil {
+		t.Fatalf("RecommendForTicket() error = %v", err)
+	}
+	if got := section(result, ownID); got != "" {
+		t.Errorf("own ticket listed in %q", got)
+	}
+}
+
+func TestRecommendForTicketAffinityBreaksTies(t *testing.T) {
+	tests := []struct {
+		name      string
+		favourite bool // the second rider is a favourite of the owner
+		sameBatch bool // the second rider is from the owner's batch
+	}{
+		{name: "favourite", favourite: true},
+		{name: "same batch", sameBatch: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+			ctx := context.Background()
+			owner := f.user(t, uentity.User{Email: "owner@example.com", Batch: "Batch2025"})
+			stranger := f.user(t, uentity.User{Email: "stranger@example.com", Batch: "Batch2023"})
+			friendBatch := "Batch2024"
+			if tt.sameBatch {
+				friendBatch = owner.Batch
+			}
+			friend := f.user(t, uentity.User{Email: "friend@example.com", Batch: friendBatch})
+			if tt.favourite {
+				if err := f.favourites.Add(ctx, owner.ID, friend.ID); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// Both start from a station, not a hostel, which zeroes the score and leaves a tie
+			// that only the friend's affinity breaks
+			fromStation := tentity.TravelTicket{Source: smvt}
+			targetID := f.ticket(t, withRoute(tentity.TravelTicket{}, owner.ID)).ID
+			f.ticket(t, withRoute(fromStation, stranger.ID))
+			friendTicketID := f.ticket(t, withRoute(fromStation, friend.ID)).ID
+
+			result, err := f.svc.RecommendForTicket(ctx, targetID, false)
+			if err != nil {
+				t.Fatalf("RecommendForTicket() error = %v", err)
+			}
+			if result.BestMatch == nil || result.BestMatch.CandidateID != friendTicketID {
+				t.Errorf("best match = %+v, want the friend's ticket %d", result.BestMatch, friendTicketID)
+			}
+		})
+	}
+}
+
+// withRoute fills in an outbound Uniworld-1 → Terminal-1 trip at departure where tk leaves
+// fields empty
+func withRoute(tk tentity.TravelTicket, userID int64) tentity.TravelTicket {
+	tk.UserID = userID
+	if tk.Source == "" {
+		tk.Source = hostel1
+	}
+	if tk.Destination == "" {
+		tk.Destination = terminal1
+	}
+	if tk.DepartureAt.IsZero() {
+		tk.DepartureAt = departure
+	}
+	if tk.TimeDiffMins == 0 {
+		tk.TimeDiffMins = 30
+	}
+	return tk
+}
+
+// repeatTickets returns n tickets on consecutive dates from start
+func repeatTickets(n int, start time.Time) []tentity.TravelTicket {
+	out := make([]tentity.TravelTicket, 0, n)
+	for i := 0; i < n; i++ {
+		out = append(out, tentity.TravelTicket{Source: hostel1, Destination: terminal1, DepartureAt: start.AddDate(0, 0, i)})
+	}
+	return out
+}
+
+// section reports where a candidate ticket appears in a recommendation result
+func section(r *models.RecommendationResult, ticketID int64) string {
+	if r.BestMatch != nil && r.BestMatch.CandidateID == ticketID {
+		return "riders"
+	}
+	for _, list := range [][]models.ScoredTicket{r.BestGroup, r.OtherAlternatives} {
+		if containsTicketID(list, ticketID) {
+			return "riders"
+		}
+	}
+	if containsTicketID(r.AvailableRides, ticketID) {
+		return "rides"
+	}
+	return ""
+}
@@ -0,0 +1,11 @@
+package entity
+
+import "time"
+
+// FavouriteTraveller is a user saved by another user as someone they like to travel with
+type FavouriteTraveller struct {
+	ID          int64     `gorm:"primaryKey;autoIncrement;not null" json:"-"`
+	UserID      int64     `gorm:"not null;uniqueIndex:idx_favourite_travellers_pair" json:"-"`
+	FavouriteID int64     `gorm:"not null;uniqueIndex:idx_favourite_travellers_pair;index" json:"-"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
@@ -1,13 +1,23 @@
 package entity
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type User struct {
-	ID          int64     `gorm:"primaryKey;autoIncrement;not null"`
-	Name        string    `gorm:"size:255"`
-	Email       string    `gorm:"not null;unique"`
-	Batch       string    `gorm:"not null" `
-	PhoneNumber string    `gorm:"size:10" `
-	CreatedAt   time.Time `gorm:"autoCreateTime"`
-	UpdatedAt   time.Time `gorm:"autoUpdateTime"`
+	ID          int64          `gorm:"primaryKey;autoIncrement;not null"`
+	Name        string         `gorm:"size:255"`
+	Email       string         `gorm:"not null;uniqueIndex:idx_users_email"`
+	Batch       string         `gorm:"not null" `
+	PhoneNumber string         `gorm:"size:15" `
+	Timezone    string         `gorm:"size:64"` // IANA zone, empty means the campus default
+	Gender      string         `gorm:"size:16"` // see models.Genders, empty when not set
+	CreatedAt   time.Time      `gorm:"autoCreateTime"`
+	UpdatedAt   time.Time      `gorm:"autoUpdateTime"`
+	DeletedAt   gorm.DeletedAt `gorm:"index"`
+
+	// CalendarToken authorises the public .ics feed; nil when the feed is disabled
+	CalendarToken *string `gorm:"size:64;uniqueIndex" json:"-"`
 }
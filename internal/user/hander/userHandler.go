@@ -1,8 +1,11 @@
 package handler
 
 import (
+	"Travel_Sync/internal/apperr"
+	"Travel_Sync/internal/middleware"
 	"Travel_Sync/internal/user/models"
 	"Travel_Sync/internal/user/service"
+	"Travel_Sync/internal/validation"
 	"net/http"
 	"strconv"
 
@@ -20,12 +23,12 @@ func NewUserHandler(svc *service.UserService) *UserHandler {
 func getIDParam(c *gin.Context) (int64, bool) {
 	idStr := c.Param("id")
 	if idStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "id is required"})
+		c.Error(apperr.ErrInvalidID.WithMessage("id is required"))
 		return 0, false
 	}
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil || id <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid id"})
+		c.Error(apperr.ErrInvalidID)
 		return 0, false
 	}
 	return id, true
@@ -45,31 +48,30 @@ func getIDParam(c *gin.Context) (int64, bool) {
 func (u *UserHandler) UpdateUser(c *gin.Context) {
 	id, ok := getIDParam(c)
 	if !ok {
-		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Id parsing failed"})
 		return
 	}
 
 	// Ownership check: user can update only their own profile
 	uid, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "unauthorized"})
+		c.Error(apperr.ErrUnauthorized)
 		return
 	}
 	currentUserID := toInt64(uid)
 	if currentUserID != id {
-		c.JSON(http.StatusForbidden, gin.H{"success": false, "error": "forbidden"})
+		c.Error(apperr.ErrForbidden)
 		return
 	}
 
 	var dto models.UserUpdateDto
 	if err := c.ShouldBindJSON(&dto); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid request body"})
+		c.Error(validation.BindError(err))
 		return
 	}
 
-	user, err := u.svc.UpdateUser(id, &dto)
+	user, err := u.svc.UpdateUser(c.Request.Context(), id, &dto, middleware.GetRequestID(c))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -89,24 +91,23 @@ func toInt64(v interface{}) int64 {
 func (u *UserHandler) DeleteUser(c *gin.Context) {
 	id, ok := getIDParam(c)
 	if !ok {
-		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Id parsing failed"})
 		return
 	}
 
     // Ownership check: user can delete only their own profile
     uid, exists := c.Get("user_id")
     if !exists {
-        c.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "unauthorized"})
+        c.Error(apperr.ErrUnauthorized)
         return
     }
     currentUserID := toInt64(uid)
     if currentUserID != id {
-        c.JSON(http.StatusForbidden, gin.H{"success": false, "error": "forbidden"})
+        c.Error(apperr.ErrForbidden)
         return
     }
 
-	if err := u.svc.DeleteByID(id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "Failed to delete user"})
+	if err := u.svc.DeleteByID(c.Request.Context(), id, middleware.GetRequestID(c)); err != nil {
+		c.Error(err)
 		return
 	}
 
@@ -116,13 +117,12 @@ func (u *UserHandler) DeleteUser(c *gin.Context) {
 func (u *UserHandler) GetUserById(c *gin.Context) {
 	id, ok := getIDParam(c)
 	if !ok {
-		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Id parsing failed"})
 		return
 	}
 
-	user, err := u.svc.GetByID(id)
+	user, err := u.svc.GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": "User not found"})
+		c.Error(err)
 		return
 	}
 
@@ -130,11 +130,47 @@ func (u *UserHandler) GetUserById(c *gin.Context) {
 }
 
 func (u *UserHandler) GetAllUser(c *gin.Context) {
-	users, err := u.svc.GetAll()
+	users, err := u.svc.GetAll(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "Failed to get all users"})
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"success": true, "data": users})
 }
+
+// ListFavourites returns the caller's favourite travellers
+func (u *UserHandler) ListFavourites(c *gin.Context) {
+	uid, _ := c.Get("user_id")
+	favs, err := u.svc.ListFavourites(c.Request.Context(), toInt64(uid))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": favs})
+}
+
+// AddFavourite saves another user, by email, as a favourite traveller
+func (u *UserHandler) AddFavourite(c *gin.Context) {
+	uid, _ := c.Get("user_id")
+	var dto models.FavouriteAddDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.Error(validation.BindError(err))
+		return
+	}
+	if err := u.svc.AddFavourite(c.Request.Context(), toInt64(uid), dto.Email); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": "Favourite added"})
+}
+
+// RemoveFavourite removes a favourite traveller by email
+func (u *UserHandler) RemoveFavourite(c *gin.Context) {
+	uid, _ := c.Get("user_id")
+	if err := u.svc.RemoveFavourite(c.Request.Context(), toInt64(uid), c.Param("email")); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": "Favourite removed"})
+}
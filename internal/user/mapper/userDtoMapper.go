@@ -20,6 +20,12 @@ func FromUserUpdateDto(updateDto *models.UserUpdateDto, user *entity.User) *enti
 	if updateDto.PhoneNumber != "" {
 		user.PhoneNumber = updateDto.PhoneNumber
 	}
+	if updateDto.Timezone != nil {
+		user.Timezone = *updateDto.Timezone
+	}
+	if updateDto.Gender != nil {
+		user.Gender = *updateDto.Gender
+	}
 	return user
 }
 
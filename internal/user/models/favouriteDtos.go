@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+type FavouriteAddDto struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type FavouriteTravellerDto struct {
+	Name    string    `json:"name"`
+	Batch   string    `json:"batch"`
+	Email   string    `json:"email"`
+	AddedAt time.Time `json:"added_at"`
+}
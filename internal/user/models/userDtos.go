@@ -5,7 +5,23 @@ type UserCreateDto struct {
 	Batch string `validate:"required,gte=1"`
 }
 
+// UserUpdateDto changes the fields that are set. Timezone and Gender are pointers so that an
+// explicit "" clears them, while leaving them out keeps the stored value.
 type UserUpdateDto struct {
-    Name        string `json:"name" validate:"max=255"`
-    PhoneNumber string `json:"phone_number" validate:"phone"`
+    Name        string  `json:"name" binding:"max=255"`
+    PhoneNumber string  `json:"phone_number" binding:"omitempty,phone"`
+    Timezone    *string `json:"timezone" binding:"omitnil,eq=|tz"`   // IANA zone, e.g. "Asia/Kolkata"; "" means the campus default
+    Gender      *string `json:"gender" binding:"omitnil,eq=|gender"` // one of Genders, used for same-gender rides; "" unsets it
+}
+
+// Genders a user can set on their profile
+var Genders = map[string]struct{}{
+	"female":     {},
+	"male":       {},
+	"non_binary": {},
+}
+
+func IsValidGender(g string) bool {
+	_, ok := Genders[g]
+	return ok
 }
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"Travel_Sync/internal/user/entity"
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type FavouriteRepo struct {
+	DB *gorm.DB
+}
+
+func NewFavouriteRepo(db *gorm.DB) *FavouriteRepo {
+	return &FavouriteRepo{DB: db}
+}
+
+// Add saves favouriteID as a favourite of userID; adding an existing favourite is a no-op
+func (r *FavouriteRepo) Add(ctx context.Context, userID, favouriteID int64) error {
+	return r.DB.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&entity.FavouriteTraveller{UserID: userID, FavouriteID: favouriteID}).Error
+}
+
+// Remove deletes a favourite, returning gorm.ErrRecordNotFound if it was not saved
+func (r *FavouriteRepo) Remove(ctx context.Context, userID, favouriteID int64) error {
+	res := r.DB.WithContext(ctx).Where("user_id = ? AND favourite_id = ?", userID, favouriteID).Delete(&entity.FavouriteTraveller{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ListByUser returns the user's favourites, most recently added first
+func (r *FavouriteRepo) ListByUser(ctx context.Context, userID int64) ([]entity.FavouriteTraveller, error) {
+	var favs []entity.FavouriteTraveller
+	err := r.DB.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").Find(&favs).Error
+	return favs, err
+}
+
+// FavouriteIDs returns the set of user IDs saved as favourites by userID
+func (r *FavouriteRepo) FavouriteIDs(ctx context.Context, userID int64) (map[int64]bool, error) {
+	var ids []int64
+	if err := r.DB.WithContext(ctx).Model(&entity.FavouriteTraveller{}).Where("user_id = ?", userID).Pluck("favourite_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	set := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set, nil
+}
@@ -0,0 +1,281 @@
+package repository
+
+import (
+	"Travel_Sync/internal/user/entity"
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MemoryUserRepo is an in-process UserRepository with the same semantics as the Postgres
+// one: soft deletes, unique emails and calendar tokens, and gorm.ErrRecordNotFound for
+// missing rows. Purging users also drops their favourites from Favourites when it is set.
+type MemoryUserRepo struct {
+	mu         sync.Mutex
+	users      map[int64]entity.User
+	nextID     int64
+	Favourites *MemoryFavouriteRepo
+}
+
+func NewMemoryUserRepo(favourites *MemoryFavouriteRepo) *MemoryUserRepo {
+	return &MemoryUserRepo{users: make(map[int64]entity.User), nextID: 1, Favourites: favourites}
+}
+
+func (r *MemoryUserRepo) Create(ctx context.Context, user *entity.User) (*entity.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if user.ID == 0 {
+		user.ID = r.nextID
+	} else if _, exists := r.users[user.ID]; exists {
+		return user, gorm.ErrDuplicatedKey
+	}
+	if r.conflicts(user) {
+		return user, gorm.ErrDuplicatedKey
+	}
+	if user.ID >= r.nextID {
+		r.nextID = user.ID + 1
+	}
+	now := time.Now()
+	if user.CreatedAt.IsZero() {
+		user.CreatedAt = now
+	}
+	user.UpdatedAt = now
+	r.users[user.ID] = copyUser(*user)
+	return user, nil
+}
+
+func (r *MemoryUserRepo) GetByID(ctx context.Context, userID int64) (*entity.User, error) {
+	return r.first(func(u *entity.User) bool { return u.ID == userID })
+}
+
+func (r *MemoryUserRepo) GetByIDs(ctx context.Context, userIDs []int64) ([]entity.User, error) {
+	ids := make(map[int64]bool, len(userIDs))
+	for _, id := range userIDs {
+		ids[id] = true
+	}
+	return r.find(func(u *entity.User) bool { return ids[u.ID] }), nil
+}
+
+func (r *MemoryUserRepo) GetAll(ctx context.Context) ([]entity.User, error) {
+	return r.find(func(u *entity.User) bool { return true }), nil
+}
+
+// UpdateUser saves every field like gorm's Save, creating the user when it does not exist
+func (r *MemoryUserRepo) UpdateUser(ctx context.Context, user *entity.User) (*entity.User, error) {
+	if user.ID == 0 {
+		return r.Create(ctx, user)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.conflicts(user) {
+		return user, gorm.ErrDuplicatedKey
+	}
+	user.UpdatedAt = time.Now()
+	r.users[user.ID] = copyUser(*user)
+	if user.ID >= r.nextID {
+		r.nextID = user.ID + 1
+	}
+	return user, nil
+}
+
+func (r *MemoryUserRepo) Delete(ctx context.Context, userID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if u, ok := r.users[userID]; ok && !u.DeletedAt.Valid {
+		u.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+		r.users[userID] = u
+	}
+	return nil
+}
+
+func (r *MemoryUserRepo) GetByCalendarToken(ctx context.Context, token string) (*entity.User, error) {
+	return r.first(func(u *entity.User) bool { return u.CalendarToken != nil && *u.CalendarToken == token })
+}
+
+func (r *MemoryUserRepo) GetDeletedByEmail(ctx context.Context, email string) (*entity.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, u := range r.users {
+		if u.Email == email && u.DeletedAt.Valid {
+			u = copyUser(u)
+			return &u, nil
+		}
+	}
+	return &entity.User{}, gorm.ErrRecordNotFound
+}
+
+func (r *MemoryUserRepo) Restore(ctx context.Context, userID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if u, ok := r.users[userID]; ok {
+		u.DeletedAt = gorm.DeletedAt{}
+		r.users[userID] = u
+	}
+	return nil
+}
+
+func (r *MemoryUserRepo) GetIDsDeletedBefore(ctx context.Context, cutoff time.Time) ([]int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ids := make([]int64, 0)
+	for _, u := range r.users {
+		if u.DeletedAt.Valid && u.DeletedAt.Time.Before(cutoff) {
+			ids = append(ids, u.ID)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
+
+func (r *MemoryUserRepo) PurgeByIDs(ctx context.Context, userIDs []int64) (int64, error) {
+	if len(userIDs) == 0 {
+		return 0, nil
+	}
+	if r.Favourites != nil {
+		r.Favourites.removeUsers(userIDs)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var n int64
+	for _, id := range userIDs {
+		if _, ok := r.users[id]; ok {
+			delete(r.users, id)
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *MemoryUserRepo) GetUserByEmail(ctx context.Context, email string) (*entity.User, error) {
+	return r.first(func(u *entity.User) bool { return u.Email == email })
+}
+
+// first returns a copy of the first live user matching keep, like gorm's First an empty
+// user alongside the error when there is none
+func (r *MemoryUserRepo) first(keep func(*entity.User) bool) (*entity.User, error) {
+	users := r.find(keep)
+	if len(users) == 0 {
+		return &entity.User{}, gorm.ErrRecordNotFound
+	}
+	return &users[0], nil
+}
+
+// find returns copies of the live users matching keep, in ID order
+func (r *MemoryUserRepo) find(keep func(*entity.User) bool) []entity.User {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	users := make([]entity.User, 0)
+	for _, u := range r.users {
+		if !u.DeletedAt.Valid && keep(&u) {
+			users = append(users, copyUser(u))
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	return users
+}
+
+// conflicts reports whether another user, deleted or not, has the same email or calendar
+// token; the caller holds mu
+func (r *MemoryUserRepo) conflicts(user *entity.User) bool {
+	for _, u := range r.users {
+		if u.ID == user.ID {
+			continue
+		}
+		if u.Email == user.Email {
+			return true
+		}
+		if u.CalendarToken != nil && user.CalendarToken != nil && *u.CalendarToken == *user.CalendarToken {
+			return true
+		}
+	}
+	return false
+}
+
+func copyUser(u entity.User) entity.User {
+	if u.CalendarToken != nil {
+		token := *u.CalendarToken
+		u.CalendarToken = &token
+	}
+	return u
+}
+
+// MemoryFavouriteRepo is an in-process FavouriteRepository
+type MemoryFavouriteRepo struct {
+	mu     sync.Mutex
+	favs   []entity.FavouriteTraveller
+	nextID int64
+}
+
+func NewMemoryFavouriteRepo() *MemoryFavouriteRepo {
+	return &MemoryFavouriteRepo{nextID: 1}
+}
+
+func (r *MemoryFavouriteRepo) Add(ctx context.Context, userID, favouriteID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, f := range r.favs {
+		if f.UserID == userID && f.FavouriteID == favouriteID {
+			return nil
+		}
+	}
+	r.favs = append(r.favs, entity.FavouriteTraveller{ID: r.nextID, UserID: userID, FavouriteID: favouriteID, CreatedAt: time.Now()})
+	r.nextID++
+	return nil
+}
+
+func (r *MemoryFavouriteRepo) Remove(ctx context.Context, userID, favouriteID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, f := range r.favs {
+		if f.UserID == userID && f.FavouriteID == favouriteID {
+			r.favs = append(r.favs[:i], r.favs[i+1:]...)
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (r *MemoryFavouriteRepo) ListByUser(ctx context.Context, userID int64) ([]entity.FavouriteTraveller, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	favs := make([]entity.FavouriteTraveller, 0)
+	// Newest last in the slice, newest first in the result
+	for i := len(r.favs) - 1; i >= 0; i-- {
+		if r.favs[i].UserID == userID {
+			favs = append(favs, r.favs[i])
+		}
+	}
+	return favs, nil
+}
+
+func (r *MemoryFavouriteRepo) FavouriteIDs(ctx context.Context, userID int64) (map[int64]bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	set := make(map[int64]bool)
+	for _, f := range r.favs {
+		if f.UserID == userID {
+			set[f.FavouriteID] = true
+		}
+	}
+	return set, nil
+}
+
+// removeUsers drops favourites saved by or pointing at any of userIDs
+func (r *MemoryFavouriteRepo) removeUsers(userIDs []int64) {
+	ids := make(map[int64]bool, len(userIDs))
+	for _, id := range userIDs {
+		ids[id] = true
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	kept := r.favs[:0]
+	for _, f := range r.favs {
+		if !ids[f.UserID] && !ids[f.FavouriteID] {
+			kept = append(kept, f)
+		}
+	}
+	r.favs = kept
+}
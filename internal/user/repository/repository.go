@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"Travel_Sync/internal/user/entity"
+	"context"
+	"time"
+)
+
+// UserRepository is the user storage the services depend on. UserRepo is the Postgres
+// implementation; MemoryUserRepo keeps users in process.
+type UserRepository interface {
+	Create(ctx context.Context, user *entity.User) (*entity.User, error)
+	GetByID(ctx context.Context, userID int64) (*entity.User, error)
+	GetByIDs(ctx context.Context, userIDs []int64) ([]entity.User, error)
+	GetAll(ctx context.Context) ([]entity.User, error)
+	UpdateUser(ctx context.Context, user *entity.User) (*entity.User, error)
+	Delete(ctx context.Context, userID int64) error
+	GetByCalendarToken(ctx context.Context, token string) (*entity.User, error)
+	GetDeletedByEmail(ctx context.Context, email string) (*entity.User, error)
+	Restore(ctx context.Context, userID int64) error
+	GetIDsDeletedBefore(ctx context.Context, cutoff time.Time) ([]int64, error)
+	PurgeByIDs(ctx context.Context, userIDs []int64) (int64, error)
+	GetUserByEmail(ctx context.Context, email string) (*entity.User, error)
+}
+
+// FavouriteRepository stores favourite travellers; see FavouriteRepo and MemoryFavouriteRepo
+type FavouriteRepository interface {
+	Add(ctx context.Context, userID, favouriteID int64) error
+	Remove(ctx context.Context, userID, favouriteID int64) error
+	ListByUser(ctx context.Context, userID int64) ([]entity.FavouriteTraveller, error)
+	FavouriteIDs(ctx context.Context, userID int64) (map[int64]bool, error)
+}
+
+var (
+	_ UserRepository      = (*UserRepo)(nil)
+	_ UserRepository      = (*MemoryUserRepo)(nil)
+	_ FavouriteRepository = (*FavouriteRepo)(nil)
+	_ FavouriteRepository = (*MemoryFavouriteRepo)(nil)
+)
@@ -2,6 +2,8 @@ package repository
 
 import (
 	"Travel_Sync/internal/user/entity"
+	"context"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -15,39 +17,91 @@ func NewUserRepo(db *gorm.DB) *UserRepo {
 }
 
 // Create a new user
-func (r *UserRepo) Create(user *entity.User) (*entity.User, error) {
-	err := r.DB.Create(&user).Error
+func (r *UserRepo) Create(ctx context.Context, user *entity.User) (*entity.User, error) {
+	err := r.DB.WithContext(ctx).Create(&user).Error
 	return user, err
 }
 
 // GetById Get User by ID
-func (r *UserRepo) GetByID(userID int64) (*entity.User, error) {
+func (r *UserRepo) GetByID(ctx context.Context, userID int64) (*entity.User, error) {
 	var user entity.User
-	err := r.DB.First(&user, userID).Error
+	err := r.DB.WithContext(ctx).First(&user, userID).Error
 	return &user, err
 }
 
+// GetByIDs returns the users with the given IDs, in no particular order
+func (r *UserRepo) GetByIDs(ctx context.Context, userIDs []int64) ([]entity.User, error) {
+	var users []entity.User
+	if len(userIDs) == 0 {
+		return users, nil
+	}
+	err := r.DB.WithContext(ctx).Where("id IN ?", userIDs).Find(&users).Error
+	return users, err
+}
+
 // GetAll User
-func (r *UserRepo) GetAll() ([]entity.User, error) {
+func (r *UserRepo) GetAll(ctx context.Context) ([]entity.User, error) {
 	var users []entity.User
-	err := r.DB.Find(&users).Error
+	err := r.DB.WithContext(ctx).Find(&users).Error
 	return users, err
 }
 
 // Update User
-func (r *UserRepo) UpdateUser(user *entity.User) (*entity.User, error) {
-	err := r.DB.Save(user).Error
+func (r *UserRepo) UpdateUser(ctx context.Context, user *entity.User) (*entity.User, error) {
+	err := r.DB.WithContext(ctx).Save(user).Error
 	return user, err
 }
 
-// Delete User By ID
-func (r *UserRepo) Delete(userID int64) error {
-	return r.DB.Delete(&entity.User{ID: userID}).Error
+// Delete User By ID (soft delete, restorable until purged)
+func (r *UserRepo) Delete(ctx context.Context, userID int64) error {
+	return r.DB.WithContext(ctx).Delete(&entity.User{ID: userID}).Error
+}
+
+// GetByCalendarToken returns the user owning the calendar feed token
+func (r *UserRepo) GetByCalendarToken(ctx context.Context, token string) (*entity.User, error) {
+	var user entity.User
+	err := r.DB.WithContext(ctx).First(&user, "calendar_token = ?", token).Error
+	return &user, err
+}
+
+// GetDeletedByEmail returns a soft-deleted user by email
+func (r *UserRepo) GetDeletedByEmail(ctx context.Context, email string) (*entity.User, error) {
+	var user entity.User
+	err := r.DB.WithContext(ctx).Unscoped().Where("email = ? AND deleted_at IS NOT NULL", email).First(&user).Error
+	return &user, err
+}
+
+// Restore clears the soft-delete marker of a user
+func (r *UserRepo) Restore(ctx context.Context, userID int64) error {
+	return r.DB.WithContext(ctx).Unscoped().Model(&entity.User{}).
+		Where("id = ?", userID).
+		Update("deleted_at", nil).Error
+}
+
+// GetIDsDeletedBefore returns IDs of users soft-deleted before cutoff
+func (r *UserRepo) GetIDsDeletedBefore(ctx context.Context, cutoff time.Time) ([]int64, error) {
+	var ids []int64
+	err := r.DB.WithContext(ctx).Unscoped().Model(&entity.User{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// PurgeByIDs hard-deletes the given users, along with favourites saved by or pointing at them
+func (r *UserRepo) PurgeByIDs(ctx context.Context, userIDs []int64) (int64, error) {
+	if len(userIDs) == 0 {
+		return 0, nil
+	}
+	if err := r.DB.WithContext(ctx).Where("user_id IN ? OR favourite_id IN ?", userIDs, userIDs).Delete(&entity.FavouriteTraveller{}).Error; err != nil {
+		return 0, err
+	}
+	res := r.DB.WithContext(ctx).Unscoped().Where("id IN ?", userIDs).Delete(&entity.User{})
+	return res.RowsAffected, res.Error
 }
 
 // Get user by email
-func (r *UserRepo) GetUserByEmail(email string) (*entity.User, error) {
+func (r *UserRepo) GetUserByEmail(ctx context.Context, email string) (*entity.User, error) {
 	var user entity.User
-	err := r.DB.First(&user, "email = ?", email).Error
+	err := r.DB.WithContext(ctx).First(&user, "email = ?", email).Error
 	return &user, err
 }
@@ -19,9 +19,12 @@ func RegisterUserRoutes(router *gin.Engine, userHandler *handler.UserHandler, jw
 		user.Use(middleware.GeneralRateLimiter())
 		{
 			//user.POST("", userHandler.CreateUser)
-			//user.DELETE("/:id", userHandler.DeleteUser)
+			user.GET("/favourites", userHandler.ListFavourites)
+			user.POST("/favourites", userHandler.AddFavourite)
+			user.DELETE("/favourites/:email", userHandler.RemoveFavourite)
 			user.PUT("/:id", userHandler.UpdateUser)
 			user.GET("/:id", userHandler.GetUserById)
+			user.DELETE("/:id", userHandler.DeleteUser)
 			//user.GET("", userHandler.GetAllUser)
 		}
 	}
@@ -1,72 +1,246 @@
 package service
 
 import (
+	"Travel_Sync/internal/apperr"
+	amodels "Travel_Sync/internal/audit/models"
+	aservice "Travel_Sync/internal/audit/service"
+	tentity "Travel_Sync/internal/travel/entity"
+	trepo "Travel_Sync/internal/travel/repository"
+	tservice "Travel_Sync/internal/travel/service"
 	"Travel_Sync/internal/user/entity"
 	"Travel_Sync/internal/user/mapper"
 	"Travel_Sync/internal/user/models"
 	"Travel_Sync/internal/user/repository"
+	wservice "Travel_Sync/internal/waitlist/service"
+	"context"
+	"strings"
+)
+
+var (
+	ErrUserNotFound      = apperr.NotFound("user_not_found", "User not found")
+	ErrFavouriteNotFound = apperr.NotFound("favourite_not_found", "Favourite not found")
+	ErrSelfFavourite     = apperr.Validation("self_favourite", "you cannot add yourself as a favourite")
 )
 
 type UserService struct {
-	Repo *repository.UserRepo
+	Repo       repository.UserRepository
+	Favourites repository.FavouriteRepository
+	Audit      *aservice.AuditService
+	Tickets    trepo.TravelTicketRepository
+	Waitlist   *wservice.WaitlistService
+
+	Recommendations *tservice.RecommendationCache
 }
 
-func NewUserService(repo *repository.UserRepo) *UserService {
-	return &UserService{Repo: repo}
+func NewUserService(repo repository.UserRepository, favourites repository.FavouriteRepository, audit *aservice.AuditService, tickets trepo.TravelTicketRepository, waitlist *wservice.WaitlistService, recommendations *tservice.RecommendationCache) *UserService {
+	return &UserService{Repo: repo, Favourites: favourites, Audit: audit, Tickets: tickets, Waitlist: waitlist, Recommendations: recommendations}
 }
 
-func (svc *UserService) CreateUser(email string) (*entity.User, error) {
+func (svc *UserService) CreateUser(ctx context.Context, email string) (*entity.User, error) {
 	user := mapper.FromUserEmail(email)
-	user, err := svc.Repo.Create(user)
+	user, err := svc.Repo.Create(ctx, user)
 
 	if err != nil {
 		return nil, err
 	}
+	svc.Audit.Record(ctx, amodels.AuditEntry{
+		ActorID:    user.ID,
+		OwnerID:    user.ID,
+		Action:     amodels.ActionCreate,
+		EntityType: amodels.EntityUser,
+		EntityID:   user.ID,
+		After:      user,
+	})
 	return user, nil
 }
 
-func (svc *UserService) GetByID(userID int64) (*entity.User, error) {
-	user, err := svc.Repo.GetByID(userID)
+func (svc *UserService) GetByID(ctx context.Context, userID int64) (*entity.User, error) {
+	user, err := svc.Repo.GetByID(ctx, userID)
 	if err != nil {
-		return nil, err
+		return nil, apperr.NotFoundAs(err, ErrUserNotFound)
 	}
 	return user, nil
 }
 
-func (svc *UserService) GetAll() ([]entity.User, error) {
-	users, err := svc.Repo.GetAll()
+func (svc *UserService) GetAll(ctx context.Context) ([]entity.User, error) {
+	users, err := svc.Repo.GetAll(ctx)
 	if err != nil {
 		return nil, err
 	}
 	return users, nil
 }
 
-func (svc *UserService) DeleteByID(userID int64) error {
-	err := svc.Repo.Delete(userID)
+// DeleteByID soft-deletes the account together with its tickets, which are closed first so
+// they drop out of listings and recommendations. Riders waiting on the tickets are told the
+// trips are cancelled and the user leaves every waitlist they are on. The tickets stay
+// restorable, like the account, until the purge job removes them.
+func (svc *UserService) DeleteByID(ctx context.Context, userID int64, requestID string) error {
+	user, err := svc.Repo.GetByID(ctx, userID)
+	if err != nil {
+		return apperr.NotFoundAs(err, ErrUserNotFound)
+	}
+	tickets, err := svc.Tickets.GetByUserID(ctx, userID)
 	if err != nil {
 		return err
 	}
+	deleted := make([]*tentity.TravelTicket, 0, len(tickets))
+	for i := range tickets {
+		t := &tickets[i]
+		before := *t
+		t.Status = "closed"
+		if _, err := svc.Tickets.Update(ctx, t); err != nil {
+			return err
+		}
+		if err := svc.Tickets.Delete(ctx, t.ID); err != nil {
+			return err
+		}
+		deleted = append(deleted, t)
+		svc.Audit.Record(ctx, amodels.AuditEntry{
+			ActorID:    userID,
+			OwnerID:    userID,
+			Action:     amodels.ActionDelete,
+			EntityType: amodels.EntityTicket,
+			EntityID:   t.ID,
+			Before:     &before,
+			RequestID:  requestID,
+		})
+		svc.Waitlist.CancelForTicket(context.WithoutCancel(ctx), t)
+	}
+	err = svc.Repo.Delete(ctx, userID)
+	if err != nil {
+		return err
+	}
+	svc.Waitlist.LeaveAll(context.WithoutCancel(ctx), userID)
+	svc.Recommendations.Invalidate(deleted...)
+	svc.Recommendations.InvalidateUsers(userID)
+	svc.Audit.Record(ctx, amodels.AuditEntry{
+		ActorID:    userID,
+		OwnerID:    userID,
+		Action:     amodels.ActionDelete,
+		EntityType: amodels.EntityUser,
+		EntityID:   userID,
+		Before:     user,
+		RequestID:  requestID,
+	})
 	return nil
 }
 
-func (svc *UserService) UpdateUser(userId int64, updateDto *models.UserUpdateDto) (*entity.User, error) {
-	user, err := svc.Repo.GetByID(userId)
+func (svc *UserService) UpdateUser(ctx context.Context, userId int64, updateDto *models.UserUpdateDto, requestID string) (*entity.User, error) {
+	user, err := svc.Repo.GetByID(ctx, userId)
 	if err != nil {
-		return nil, err
+		return nil, apperr.NotFoundAs(err, ErrUserNotFound)
 	}
+	before := *user
 	user = mapper.FromUserUpdateDto(updateDto, user)
 
-	user, err = svc.Repo.UpdateUser(user)
+	user, err = svc.Repo.UpdateUser(ctx, user)
 	if err != nil {
 		return nil, err
 	}
+	// Gender and batch feed recommendation filters and scores
+	svc.Recommendations.InvalidateUsers(userId)
+	svc.Audit.Record(ctx, amodels.AuditEntry{
+		ActorID:    userId,
+		OwnerID:    userId,
+		Action:     amodels.ActionUpdate,
+		EntityType: amodels.EntityUser,
+		EntityID:   userId,
+		Before:     &before,
+		After:      user,
+		RequestID:  requestID,
+	})
 	return user, nil
 }
 
-func (svc *UserService) GetUserByEmail(email string) (*entity.User, error) {
-	user, err := svc.Repo.GetUserByEmail(email)
+// RestoreByEmail brings back a soft-deleted account, e.g. when its owner signs in again
+// within the retention window. Returns gorm.ErrRecordNotFound if there is nothing to restore.
+func (svc *UserService) RestoreByEmail(ctx context.Context, email string) (*entity.User, error) {
+	user, err := svc.Repo.GetDeletedByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	if err := svc.Repo.Restore(ctx, user.ID); err != nil {
+		return nil, err
+	}
+	svc.Recommendations.InvalidateUsers(user.ID)
+	restored, err := svc.Repo.GetByID(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	svc.Audit.Record(ctx, amodels.AuditEntry{
+		ActorID:    restored.ID,
+		OwnerID:    restored.ID,
+		Action:     amodels.ActionRestore,
+		EntityType: amodels.EntityUser,
+		EntityID:   restored.ID,
+		After:      restored,
+	})
+	return restored, nil
+}
+
+func (svc *UserService) GetUserByEmail(ctx context.Context, email string) (*entity.User, error) {
+	user, err := svc.Repo.GetUserByEmail(ctx, email)
 	if err != nil {
 		return nil, err
 	}
 	return user, nil
 }
+
+// ListFavourites returns the travellers the user has saved as favourites
+func (svc *UserService) ListFavourites(ctx context.Context, userID int64) ([]models.FavouriteTravellerDto, error) {
+	favs, err := svc.Favourites.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int64, 0, len(favs))
+	for _, f := range favs {
+		ids = append(ids, f.FavouriteID)
+	}
+	users, err := svc.Repo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int64]entity.User, len(users))
+	for _, u := range users {
+		byID[u.ID] = u
+	}
+
+	out := make([]models.FavouriteTravellerDto, 0, len(favs))
+	for _, f := range favs {
+		u, ok := byID[f.FavouriteID]
+		if !ok {
+			continue // soft-deleted account, hidden until restored or purged
+		}
+		out = append(out, models.FavouriteTravellerDto{Name: u.Name, Batch: u.Batch, Email: u.Email, AddedAt: f.CreatedAt})
+	}
+	return out, nil
+}
+
+// AddFavourite saves the user with the given email as a favourite traveller
+func (svc *UserService) AddFavourite(ctx context.Context, userID int64, email string) error {
+	fav, err := svc.Repo.GetUserByEmail(ctx, strings.TrimSpace(email))
+	if err != nil {
+		return apperr.NotFoundAs(err, ErrUserNotFound)
+	}
+	if fav.ID == userID {
+		return ErrSelfFavourite
+	}
+	if err := svc.Favourites.Add(ctx, userID, fav.ID); err != nil {
+		return err
+	}
+	svc.Recommendations.InvalidateUsers(userID)
+	return nil
+}
+
+// RemoveFavourite removes the user with the given email from the favourites
+func (svc *UserService) RemoveFavourite(ctx context.Context, userID int64, email string) error {
+	fav, err := svc.Repo.GetUserByEmail(ctx, strings.TrimSpace(email))
+	if err != nil {
+		return apperr.NotFoundAs(err, ErrFavouriteNotFound)
+	}
+	if err := svc.Favourites.Remove(ctx, userID, fav.ID); err != nil {
+		return apperr.NotFoundAs(err, ErrFavouriteNotFound)
+	}
+	svc.Recommendations.InvalidateUsers(userID)
+	return nil
+}
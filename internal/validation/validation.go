@@ -0,0 +1,228 @@
+// Package validation registers the app's custom binding tags with gin's validator and turns
+// binding failures into per-field apperr validation errors.
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"Travel_Sync/internal/apperr"
+	"Travel_Sync/internal/timezone"
+	tmodels "Travel_Sync/internal/travel/models"
+	umodels "Travel_Sync/internal/user/models"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// phonePattern allows an optional + and 10 to 14 digits, e.g. 9876543210 or +919876543210,
+// so that a number never exceeds the 15 characters of the phone_number columns
+var phonePattern = regexp.MustCompile(`^\+?[0-9]{10,14}$`)
+
+// validators are the custom tags usable in binding:"..." struct tags
+var validators = map[string]validator.Func{
+	// phone is a bare phone number without spaces or dashes
+	"phone": func(fl validator.FieldLevel) bool {
+		return phonePattern.MatchString(fl.Field().String())
+	},
+	// location is one of the predefined pickup and drop locations
+	"location": func(fl validator.FieldLevel) bool {
+		return tmodels.IsValidLocation(fl.Field().String())
+	},
+	// rfc3339instant is an RFC3339 timestamp with Z or an explicit offset, i.e. a definite instant
+	"rfc3339instant": func(fl validator.FieldLevel) bool {
+		_, err := time.Parse(time.RFC3339, fl.Field().String())
+		return err == nil
+	},
+	// departure_time is rfc3339instant or a local date-time read in the request's timezone
+	"departure_time": func(fl validator.FieldLevel) bool {
+		_, err := timezone.ParseDateTime(fl.Field().String(), time.UTC)
+		return err == nil
+	},
+	// tz is an IANA zone name
+	"tz": func(fl validator.FieldLevel) bool {
+		return timezone.IsValid(fl.Field().String())
+	},
+	"gender": func(fl validator.FieldLevel) bool {
+		return umodels.IsValidGender(fl.Field().String())
+	},
+}
+
+// Register adds the custom tags to gin's validator and makes it report fields by their JSON
+// (or form) names. Call it once before serving requests.
+func Register() error {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return errors.New("validation: gin is not using go-playground/validator")
+	}
+	v.RegisterTagNameFunc(fieldName)
+	for tag, fn := range validators {
+		if err := v.RegisterValidation(tag, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func fieldName(f reflect.StructField) string {
+	for _, key := range []string{"json", "form"} {
+		name, _, _ := strings.Cut(f.Tag.Get(key), ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return f.Name
+}
+
+// BindError converts an error from ShouldBindJSON or ShouldBindQuery into an apperr error
+// naming the offending fields
+func BindError(err error) error {
+	var verrs validator.ValidationErrors
+	var typeErr *json.UnmarshalTypeError
+	var syntaxErr *json.SyntaxError
+	switch {
+	case errors.As(err, &verrs):
+		fields := make([]apperr.FieldError, 0, len(verrs))
+		for _, fe := range verrs {
+			fields = append(fields, apperr.FieldError{Field: fieldPath(fe), Code: tag(fe), Message: message(fe)})
+		}
+		return apperr.Invalid(fields...).Wrap(err)
+	case errors.As(err, &typeErr):
+		return apperr.Invalid(apperr.FieldError{Field: typeErr.Field, Code: "type", Message: "must be " + jsonType(typeErr.Type)}).Wrap(err)
+	case errors.As(err, &syntaxErr), errors.Is(err, io.ErrUnexpectedEOF):
+		return apperr.ErrInvalidBody.WithMessage("request body is not valid JSON").Wrap(err)
+	case errors.Is(err, io.EOF):
+		return apperr.ErrInvalidBody.WithMessage("request body is empty").Wrap(err)
+	}
+	return apperr.ErrInvalidBody.Wrap(err)
+}
+
+// Var checks a single value, e.g. a query parameter, against binding tags such as
+// "omitempty,rfc3339instant". It returns nil when the value is valid.
+func Var(field, value, tags string) *apperr.FieldError {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return nil
+	}
+	var verrs validator.ValidationErrors
+	if err := v.Var(value, tags); !errors.As(err, &verrs) {
+		return nil
+	}
+	return &apperr.FieldError{Field: field, Code: tag(verrs[0]), Message: message(verrs[0])}
+}
+
+// clearable prefixes the tags of optional update fields where "" clears the stored value,
+// e.g. binding:"omitnil,eq=|tz" on a *string
+const clearable = "eq=|"
+
+// tag is the failed tag, without the clearable prefix
+func tag(fe validator.FieldError) string {
+	return strings.TrimPrefix(fe.Tag(), clearable)
+}
+
+// fieldPath drops the struct name from the namespace, e.g. "TravelTicketCreateDto.waypoints[1]"
+// becomes "waypoints[1]"
+func fieldPath(fe validator.FieldError) string {
+	if _, path, ok := strings.Cut(fe.Namespace(), "."); ok {
+		return path
+	}
+	return fe.Field()
+}
+
+func message(fe validator.FieldError) string {
+	switch tag(fe) {
+	case "required":
+		return "is required"
+	case "required_without_all":
+		return "is required unless " + orList(fe.Param()) + " is set"
+	case "required_with":
+		return "is required with " + orList(fe.Param())
+	case "required_if":
+		field, value, _ := strings.Cut(fe.Param(), " ")
+		return "is required when " + snake(field) + " is " + value
+	case "min":
+		return "must be at least " + fe.Param() + unit(fe)
+	case "max":
+		return "must be at most " + fe.Param() + unit(fe)
+	case "oneof":
+		return "must be one of " + strings.Join(strings.Fields(fe.Param()), ", ")
+	case "email":
+		return "must be an email address"
+	case "datetime":
+		return "must be a date in " + fe.Param() + " format"
+	case "phone":
+		return "must be a phone number of 10 to 14 digits with an optional leading +, e.g. 9876543210"
+	case "location":
+		return "must be one of the predefined locations"
+	case "rfc3339instant":
+		return "must be an RFC3339 timestamp with Z or an offset, e.g. 2025-10-01T09:00:00Z"
+	case "departure_time":
+		return "must be RFC3339 (e.g. 2025-10-01T14:30:00+05:30) or a local time (e.g. 2025-10-01T14:30)"
+	case "tz":
+		return "must be a valid IANA zone, e.g. Asia/Kolkata"
+	case "gender":
+		return "must be one of female, male, non_binary"
+	}
+	return "is invalid"
+}
+
+// unit tells lengths of strings and lists apart from numbers in min and max messages
+func unit(fe validator.FieldError) string {
+	switch fe.Kind() {
+	case reflect.String:
+		return " characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return " items"
+	}
+	return ""
+}
+
+// orList turns validator field lists such as "FlightNumber TrainNumber" into
+// "flight_number or train_number"
+func orList(param string) string {
+	fields := strings.Fields(param)
+	for i, f := range fields {
+		fields[i] = snake(f)
+	}
+	return strings.Join(fields, " or ")
+}
+
+// snake turns a Go field name into its JSON name, e.g. FlightNumber into flight_number
+func snake(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func jsonType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Bool:
+		return "true or false"
+	case reflect.String:
+		return "a string"
+	case reflect.Slice, reflect.Array:
+		return "a list"
+	}
+	return "an object"
+}
@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+// WaitlistEntry is a rider queued for a seat on a full ticket. Entries are served in ID order.
+type WaitlistEntry struct {
+	ID         int64      `gorm:"primaryKey;autoIncrement;not null" json:"id"`
+	TicketID   int64      `gorm:"not null;index" json:"ticket_id"`
+	UserID     int64      `gorm:"not null;index" json:"-"`
+	Status     string     `gorm:"type:varchar(20);not null;default:waiting" json:"status"`
+	ExpiresAt  time.Time  `gorm:"type:timestamptz;not null;index" json:"expires_at"` // the ticket's departure
+	PromotedAt *time.Time `json:"promoted_at,omitempty"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"Travel_Sync/internal/apperr"
+	"Travel_Sync/internal/waitlist/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WaitlistHandler struct {
+	Svc *service.WaitlistService
+}
+
+func NewWaitlistHandler(svc *service.WaitlistService) *WaitlistHandler {
+	return &WaitlistHandler{Svc: svc}
+}
+
+// Join puts the caller on the waitlist of a full ticket
+func (h *WaitlistHandler) Join(c *gin.Context) {
+	userID, ticketID, ok := parseRequest(c)
+	if !ok {
+		return
+	}
+	pos, err := h.Svc.Join(c.Request.Context(), userID, ticketID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": pos})
+}
+
+// Leave takes the caller off a ticket's waitlist
+func (h *WaitlistHandler) Leave(c *gin.Context) {
+	userID, ticketID, ok := parseRequest(c)
+	if !ok {
+		return
+	}
+	if err := h.Svc.Leave(c.Request.Context(), userID, ticketID); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": "left the waitlist"})
+}
+
+// GetPosition returns the caller's place on a ticket's waitlist
+func (h *WaitlistHandler) GetPosition(c *gin.Context) {
+	userID, ticketID, ok := parseRequest(c)
+	if !ok {
+		return
+	}
+	pos, err := h.Svc.Position(c.Request.Context(), userID, ticketID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": pos})
+}
+
+// List returns a ticket's waitlist to its owner
+func (h *WaitlistHandler) List(c *gin.Context) {
+	userID, ticketID, ok := parseRequest(c)
+	if !ok {
+		return
+	}
+	riders, err := h.Svc.ListForOwner(c.Request.Context(), userID, ticketID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": riders})
+}
+
+func parseRequest(c *gin.Context) (int64, int64, bool) {
+	uid, ok := c.Get("user_id")
+	if !ok {
+		c.Error(apperr.ErrUnauthorized)
+		return 0, 0, false
+	}
+	ticketID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || ticketID <= 0 {
+		c.Error(apperr.ErrInvalidID)
+		return 0, 0, false
+	}
+	return toInt64(uid), ticketID, true
+}
+
+func toInt64(v interface{}) int64 {
+	if id, ok := v.(int64); ok {
+		return id
+	}
+	if f, ok := v.(float64); ok {
+		return int64(f)
+	}
+	return 0
+}
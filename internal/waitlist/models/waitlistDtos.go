@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Waitlist entry statuses
+const (
+	StatusWaiting   = "waiting"
+	StatusPromoted  = "promoted"  // a seat opened up and the rider was told to contact the owner
+	StatusLeft      = "left"      // the rider left the waitlist
+	StatusCancelled = "cancelled" // the ticket was deleted
+	StatusExpired   = "expired"   // the ticket departed before a seat opened up
+)
+
+// WaitlistPosition is what a waiting rider sees about their own place in the queue
+type WaitlistPosition struct {
+	TicketID  int64     `json:"ticket_id"`
+	Status    string    `json:"status"`
+	Position  int       `json:"position,omitempty"` // 1-based, only while waiting
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// WaitlistRider is one entry as seen by the ticket owner
+type WaitlistRider struct {
+	Position int       `json:"position"`
+	Name     string    `json:"name"`
+	Batch    string    `json:"batch"`
+	Email    string    `json:"email"`
+	JoinedAt time.Time `json:"joined_at"`
+}
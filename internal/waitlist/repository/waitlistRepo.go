@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"Travel_Sync/internal/waitlist/entity"
+	"Travel_Sync/internal/waitlist/models"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type WaitlistRepo struct {
+	DB *gorm.DB
+}
+
+func NewWaitlistRepo(db *gorm.DB) *WaitlistRepo {
+	return &WaitlistRepo{DB: db}
+}
+
+func (r *WaitlistRepo) Create(ctx context.Context, e *entity.WaitlistEntry) error {
+	return r.DB.WithContext(ctx).Create(e).Error
+}
+
+// GetWaiting returns the user's waiting entry for a ticket
+func (r *WaitlistRepo) GetWaiting(ctx context.Context, ticketID, userID int64) (*entity.WaitlistEntry, error) {
+	var e entity.WaitlistEntry
+	err := r.DB.WithContext(ctx).Where("ticket_id = ? AND user_id = ? AND status = ?", ticketID, userID, models.StatusWaiting).
+		First(&e).Error
+	return &e, err
+}
+
+// GetLatest returns the user's most recent entry for a ticket, whatever its status
+func (r *WaitlistRepo) GetLatest(ctx context.Context, ticketID, userID int64) (*entity.WaitlistEntry, error) {
+	var e entity.WaitlistEntry
+	err := r.DB.WithContext(ctx).Where("ticket_id = ? AND user_id = ?", ticketID, userID).
+		Order("id DESC").First(&e).Error
+	return &e, err
+}
+
+// ListWaiting returns the waiting entries of a ticket in queue order
+func (r *WaitlistRepo) ListWaiting(ctx context.Context, ticketID int64) ([]entity.WaitlistEntry, error) {
+	var list []entity.WaitlistEntry
+	err := r.DB.WithContext(ctx).Where("ticket_id = ? AND status = ?", ticketID, models.StatusWaiting).
+		Order("id ASC").Find(&list).Error
+	return list, err
+}
+
+// ListByUserID returns every entry of the user, whatever its status, oldest first
+func (r *WaitlistRepo) ListByUserID(ctx context.Context, userID int64) ([]entity.WaitlistEntry, error) {
+	var list []entity.WaitlistEntry
+	err := r.DB.WithContext(ctx).Where("user_id = ?", userID).Order("id ASC").Find(&list).Error
+	return list, err
+}
+
+// ListPromoted returns the entries promoted onto any of the tickets, in promotion order
+func (r *WaitlistRepo) ListPromoted(ctx context.Context, ticketIDs []int64) ([]entity.WaitlistEntry, error) {
+	var list []entity.WaitlistEntry
+	if len(ticketIDs) == 0 {
+		return list, nil
+	}
+	err := r.DB.WithContext(ctx).Where("ticket_id IN ? AND status = ?", ticketIDs, models.StatusPromoted).
+		Order("promoted_at, id").Find(&list).Error
+	return list, err
+}
+
+// CountAhead returns how many riders are waiting in front of the given entry
+func (r *WaitlistRepo) CountAhead(ctx context.Context, e *entity.WaitlistEntry) (int64, error) {
+	var n int64
+	err := r.DB.WithContext(ctx).Model(&entity.WaitlistEntry{}).
+		Where("ticket_id = ? AND status = ? AND id < ?", e.TicketID, models.StatusWaiting, e.ID).
+		Count(&n).Error
+	return n, err
+}
+
+// SetStatus moves the given entries to status
+func (r *WaitlistRepo) SetStatus(ctx context.Context, ids []int64, status string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	updates := map[string]interface{}{"status": status}
+	if status == models.StatusPromoted {
+		updates["promoted_at"] = time.Now().UTC()
+	}
+	return r.DB.WithContext(ctx).Model(&entity.WaitlistEntry{}).Where("id IN ?", ids).Updates(updates).Error
+}
+
+// ListWaitingExpiredBefore returns waiting entries whose ticket departed before cutoff
+func (r *WaitlistRepo) ListWaitingExpiredBefore(ctx context.Context, cutoff time.Time) ([]entity.WaitlistEntry, error) {
+	var list []entity.WaitlistEntry
+	err := r.DB.WithContext(ctx).Where("status = ? AND expires_at < ?", models.StatusWaiting, cutoff).Find(&list).Error
+	return list, err
+}
+
+// UpdateExpiry moves the expiry of a ticket's waiting entries, e.g. after its departure changed
+func (r *WaitlistRepo) UpdateExpiry(ctx context.Context, ticketID int64, expiresAt time.Time) error {
+	return r.DB.WithContext(ctx).Model(&entity.WaitlistEntry{}).
+		Where("ticket_id = ? AND status = ?", ticketID, models.StatusWaiting).
+		Update("expires_at", expiresAt).Error
+}
+
+// PromotedPartnerIDs returns the users who shared a trip that departed before cutoff with
+// userID through the waitlist: owners of tickets the user was promoted onto, and riders
+// promoted onto the user's tickets. Deleted tickets still count.
+func (r *WaitlistRepo) PromotedPartnerIDs(ctx context.Context, userID int64, cutoff time.Time) ([]int64, error) {
+	var ids []int64
+	err := r.DB.WithContext(ctx).Raw(`SELECT DISTINCT CASE WHEN w.user_id = ? THEN t.user_id ELSE w.user_id END
+FROM waitlist_entries w JOIN travel_tickets t ON t.id = w.ticket_id
+WHERE w.status = ? AND t.departure_at < ? AND (w.user_id = ? OR t.user_id = ?)`,
+		userID, models.StatusPromoted, cutoff, userID, userID).Scan(&ids).Error
+	return ids, err
+}
+
+// DeleteByUserIDs removes the users' own entries and all entries on tickets they own. Run it
+// before the tickets themselves are purged.
+func (r *WaitlistRepo) DeleteByUserIDs(ctx context.Context, userIDs []int64) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	return r.DB.WithContext(ctx).Where("user_id IN ? OR ticket_id IN (SELECT id FROM travel_tickets WHERE user_id IN ?)", userIDs, userIDs).
+		Delete(&entity.WaitlistEntry{}).Error
+}
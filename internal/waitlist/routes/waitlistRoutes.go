@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"Travel_Sync/internal/middleware"
+	"Travel_Sync/internal/security/config"
+	"Travel_Sync/internal/security/service"
+	"Travel_Sync/internal/waitlist/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterWaitlistRoutes(router *gin.Engine, waitlistHandler *handler.WaitlistHandler, jwtService *service.JWTService) {
+	api := router.Group("/api")
+	{
+		waitlist := api.Group("/travel/:id/waitlist")
+		waitlist.Use(config.JWTMiddleware(jwtService))
+		waitlist.Use(middleware.GeneralRateLimiter())
+		{
+			waitlist.POST("", waitlistHandler.Join)
+			waitlist.DELETE("", waitlistHandler.Leave)
+			waitlist.GET("", waitlistHandler.List)
+			waitlist.GET("/me", waitlistHandler.GetPosition)
+		}
+	}
+}
@@ -0,0 +1,313 @@
+package service
+
+import (
+	"Travel_Sync/internal/apperr"
+	nservice "Travel_Sync/internal/notification/service"
+	"Travel_Sync/internal/timezone"
+	tentity "Travel_Sync/internal/travel/entity"
+	tmodels "Travel_Sync/internal/travel/models"
+	trepo "Travel_Sync/internal/travel/repository"
+	uentity "Travel_Sync/internal/user/entity"
+	urepo "Travel_Sync/internal/user/repository"
+	"Travel_Sync/internal/waitlist/entity"
+	"Travel_Sync/internal/waitlist/models"
+	"Travel_Sync/internal/waitlist/repository"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrOwnTicket      = apperr.Validation("own_ticket", "you cannot join the waitlist of your own ticket")
+	ErrTicketNotFull  = apperr.Conflict("ticket_not_full", "this ticket still has free seats, contact the owner directly")
+	ErrDeparted       = apperr.Conflict("ticket_departed", "this trip has already departed")
+	ErrAlreadyWaiting = apperr.Conflict("already_waiting", "you are already on the waitlist")
+	ErrGenderLimited  = apperr.Forbidden("gender_limited", "this ticket is limited to riders of the owner's gender")
+	ErrNoSeatsOffered = apperr.Conflict("no_seats_offered", "this ticket is a ride request and has no seats to wait for")
+	ErrNotTicketOwner = apperr.Forbidden("not_ticket_owner", "forbidden")
+	ErrTicketNotFound = apperr.NotFound("ticket_not_found", "ticket not found")
+	ErrNotOnWaitlist  = apperr.NotFound("not_on_waitlist", "you are not on the waitlist")
+)
+
+type WaitlistService struct {
+	Repo          *repository.WaitlistRepo
+	TicketRepo    trepo.TravelTicketRepository
+	UserRepo      urepo.UserRepository
+	Notifications *nservice.NotificationService
+}
+
+func NewWaitlistService(repo *repository.WaitlistRepo, ticketRepo trepo.TravelTicketRepository, userRepo urepo.UserRepository, notifications *nservice.NotificationService) *WaitlistService {
+	return &WaitlistService{Repo: repo, TicketRepo: ticketRepo, UserRepo: userRepo, Notifications: notifications}
+}
+
+// Join queues the user for a seat on a full (closed) ticket
+func (s *WaitlistService) Join(ctx context.Context, userID, ticketID int64) (*models.WaitlistPosition, error) {
+	ticket, err := s.TicketRepo.GetByID(ctx, ticketID)
+	if err != nil {
+		return nil, apperr.NotFoundAs(err, ErrTicketNotFound)
+	}
+	if ticket.UserID == userID {
+		return nil, ErrOwnTicket
+	}
+	if !ticket.DepartureAt.After(time.Now()) {
+		return nil, ErrDeparted
+	}
+	if ticket.Status != "closed" {
+		return nil, ErrTicketNotFull
+	}
+	// A rider on the waitlist wants a seat, like a sharer would
+	if !tmodels.TypesCompatible(ticket.Type, tmodels.TicketTypeShare) {
+		return nil, ErrNoSeatsOffered
+	}
+
+	owner, err := s.UserRepo.GetByID(ctx, ticket.UserID)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.UserRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !tmodels.GenderCompatible(ticket.GenderPreference, owner.Gender, tmodels.GenderPrefAny, user.Gender) {
+		return nil, ErrGenderLimited
+	}
+
+	// A unique index on waiting entries turns a second join, concurrent or not, into a duplicate
+	e := &entity.WaitlistEntry{TicketID: ticketID, UserID: userID, Status: models.StatusWaiting, ExpiresAt: ticket.DepartureAt}
+	if err := s.Repo.Create(ctx, e); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, ErrAlreadyWaiting.Wrap(err)
+		}
+		return nil, err
+	}
+	return s.position(ctx, e)
+}
+
+// Leave takes the user off a ticket's waitlist
+func (s *WaitlistService) Leave(ctx context.Context, userID, ticketID int64) error {
+	e, err := s.Repo.GetWaiting(ctx, ticketID, userID)
+	if err != nil {
+		return apperr.NotFoundAs(err, ErrNotOnWaitlist)
+	}
+	return s.Repo.SetStatus(ctx, []int64{e.ID}, models.StatusLeft)
+}
+
+// LeaveAll takes the user off every waitlist they are on, e.g. when the account is deleted.
+// Errors are only logged. A nil service is a no-op.
+func (s *WaitlistService) LeaveAll(ctx context.Context, userID int64) {
+	if s == nil {
+		return
+	}
+	entries, err := s.Repo.ListByUserID(ctx, userID)
+	if err != nil {
+		slog.ErrorContext(ctx, "waitlist: failed to list entries", "user_id", userID, "error", err)
+		return
+	}
+	ids := make([]int64, 0, len(entries))
+	for _, e := range entries {
+		if e.Status == models.StatusWaiting {
+			ids = append(ids, e.ID)
+		}
+	}
+	if err := s.Repo.SetStatus(ctx, ids, models.StatusLeft); err != nil {
+		slog.ErrorContext(ctx, "waitlist: failed to leave waitlists", "user_id", userID, "error", err)
+	}
+}
+
+// Position returns the user's latest waitlist entry for a ticket
+func (s *WaitlistService) Position(ctx context.Context, userID, ticketID int64) (*models.WaitlistPosition, error) {
+	e, err := s.Repo.GetLatest(ctx, ticketID, userID)
+	if err != nil {
+		return nil, apperr.NotFoundAs(err, ErrNotOnWaitlist)
+	}
+	return s.position(ctx, e)
+}
+
+// ListForOwner returns the queue of a ticket; only its owner may see it
+func (s *WaitlistService) ListForOwner(ctx context.Context, ownerID, ticketID int64) ([]models.WaitlistRider, error) {
+	ticket, err := s.TicketRepo.GetByID(ctx, ticketID)
+	if err != nil {
+		return nil, apperr.NotFoundAs(err, ErrTicketNotFound)
+	}
+	if ticket.UserID != ownerID {
+		return nil, ErrNotTicketOwner
+	}
+	entries, err := s.Repo.ListWaiting(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	users, err := s.usersOf(ctx, entries)
+	if err != nil {
+		return nil, err
+	}
+	riders := make([]models.WaitlistRider, 0, len(entries))
+	for i, e := range entries {
+		u := users[e.UserID]
+		riders = append(riders, models.WaitlistRider{Position: i + 1, Name: u.Name, Batch: u.Batch, Email: u.Email, JoinedAt: e.CreatedAt})
+	}
+	return riders, nil
+}
+
+// PromoteNext moves the first seats riders off the waitlist and notifies them and the owner.
+// It runs after a ticket change has been saved, so errors are only logged. A nil service is a no-op.
+func (s *WaitlistService) PromoteNext(ctx context.Context, ticket *tentity.TravelTicket, seats int) {
+	if s == nil || seats <= 0 {
+		return
+	}
+	entries, err := s.Repo.ListWaiting(ctx, ticket.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "waitlist: failed to list waitlist", "ticket_id", ticket.ID, "error", err)
+		return
+	}
+	if len(entries) == 0 {
+		return
+	}
+	if len(entries) > seats {
+		entries = entries[:seats]
+	}
+	if err := s.Repo.SetStatus(ctx, entryIDs(entries), models.StatusPromoted); err != nil {
+		slog.ErrorContext(ctx, "waitlist: failed to promote riders", "ticket_id", ticket.ID, "error", err)
+		return
+	}
+
+	users, err := s.usersOf(ctx, entries)
+	if err != nil {
+		slog.ErrorContext(ctx, "waitlist: failed to load promoted riders", "ticket_id", ticket.ID, "error", err)
+	}
+	for _, e := range entries {
+		u := users[e.UserID]
+		s.Notifications.Notify(ctx, e.UserID, nservice.KindWaitlistPromoted,
+			fmt.Sprintf("A seat opened up on %s. Contact the ticket owner to confirm.", tripLabel(ticket, &u)),
+			&ticket.ID)
+	}
+	if owner, err := s.UserRepo.GetByID(ctx, ticket.UserID); err == nil {
+		s.Notifications.Notify(ctx, owner.ID, nservice.KindWaitlistPromoted,
+			fmt.Sprintf("%d rider(s) from the waitlist were told a seat opened up on %s.", len(entries), tripLabel(ticket, owner)),
+			&ticket.ID)
+	}
+}
+
+// CancelForTicket closes the waitlist of a deleted ticket and tells the waiting riders
+func (s *WaitlistService) CancelForTicket(ctx context.Context, ticket *tentity.TravelTicket) {
+	if s == nil {
+		return
+	}
+	entries, err := s.Repo.ListWaiting(ctx, ticket.ID)
+	if err != nil || len(entries) == 0 {
+		if err != nil {
+			slog.ErrorContext(ctx, "waitlist: failed to list waitlist", "ticket_id", ticket.ID, "error", err)
+		}
+		return
+	}
+	if err := s.Repo.SetStatus(ctx, entryIDs(entries), models.StatusCancelled); err != nil {
+		slog.ErrorContext(ctx, "waitlist: failed to cancel waitlist", "ticket_id", ticket.ID, "error", err)
+		return
+	}
+	s.NotifyCancelled(ctx, ticket, entries)
+}
+
+// NotifyCancelled tells the riders of entries that the ticket they were waiting for is gone.
+// CancelForTicket calls it; callers that remove the entries themselves, such as account
+// deletion, call it with the entries they removed.
+func (s *WaitlistService) NotifyCancelled(ctx context.Context, ticket *tentity.TravelTicket, entries []entity.WaitlistEntry) {
+	if s == nil || len(entries) == 0 {
+		return
+	}
+	users, _ := s.usersOf(ctx, entries)
+	for _, e := range entries {
+		u := users[e.UserID]
+		s.Notifications.Notify(ctx, e.UserID, nservice.KindWaitlistCancelled,
+			fmt.Sprintf("The trip %s you were waiting for was cancelled.", tripLabel(ticket, &u)),
+			&ticket.ID)
+	}
+}
+
+// TicketRescheduled keeps waiting entries expiring at the ticket's new departure time
+func (s *WaitlistService) TicketRescheduled(ctx context.Context, ticket *tentity.TravelTicket) {
+	if s == nil {
+		return
+	}
+	if err := s.Repo.UpdateExpiry(ctx, ticket.ID, ticket.DepartureAt); err != nil {
+		slog.ErrorContext(ctx, "waitlist: failed to move expiry", "ticket_id", ticket.ID, "error", err)
+	}
+}
+
+// ExpireStale marks entries whose trip has departed as expired and tells the riders
+func (s *WaitlistService) ExpireStale(ctx context.Context) (int, error) {
+	entries, err := s.Repo.ListWaitingExpiredBefore(ctx, time.Now().UTC())
+	if err != nil {
+		return 0, err
+	}
+	if len(entries) == 0 {
+		return 0, nil
+	}
+	if err := s.Repo.SetStatus(ctx, entryIDs(entries), models.StatusExpired); err != nil {
+		return 0, err
+	}
+	for _, e := range entries {
+		ticketID := e.TicketID
+		s.Notifications.Notify(ctx, e.UserID, nservice.KindWaitlistExpired,
+			"No seat opened up before the trip you were waiting for departed.", &ticketID)
+	}
+	return len(entries), nil
+}
+
+// CoTravellers returns the users the given user has travelled with before, i.e. shared a
+// departed trip with after a waitlist promotion. A nil service knows of none.
+func (s *WaitlistService) CoTravellers(ctx context.Context, userID int64) (map[int64]bool, error) {
+	if s == nil {
+		return map[int64]bool{}, nil
+	}
+	ids, err := s.Repo.PromotedPartnerIDs(ctx, userID, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		out[id] = true
+	}
+	return out, nil
+}
+
+func (s *WaitlistService) position(ctx context.Context, e *entity.WaitlistEntry) (*models.WaitlistPosition, error) {
+	p := &models.WaitlistPosition{TicketID: e.TicketID, Status: e.Status, ExpiresAt: e.ExpiresAt}
+	if e.Status == models.StatusWaiting {
+		ahead, err := s.Repo.CountAhead(ctx, e)
+		if err != nil {
+			return nil, err
+		}
+		p.Position = int(ahead) + 1
+	}
+	return p, nil
+}
+
+func (s *WaitlistService) usersOf(ctx context.Context, entries []entity.WaitlistEntry) (map[int64]uentity.User, error) {
+	ids := make([]int64, 0, len(entries))
+	for _, e := range entries {
+		ids = append(ids, e.UserID)
+	}
+	users, err := s.UserRepo.GetByIDs(ctx, ids)
+	byID := make(map[int64]uentity.User, len(users))
+	for _, u := range users {
+		byID[u.ID] = u
+	}
+	return byID, err
+}
+
+// tripLabel describes a trip in the reader's zone, e.g. "Uniworld-1 → SMVT (02 Oct 06:40)"
+func tripLabel(ticket *tentity.TravelTicket, reader *uentity.User) string {
+	local := ticket.DepartureAt.In(timezone.Resolve(reader.Timezone))
+	return fmt.Sprintf("%s → %s (%s)", ticket.Source, ticket.Destination, local.Format("02 Jan 15:04"))
+}
+
+func entryIDs(entries []entity.WaitlistEntry) []int64 {
+	ids := make([]int64, 0, len(entries))
+	for _, e := range entries {
+		ids = append(ids, e.ID)
+	}
+	return ids
+}